@@ -0,0 +1,110 @@
+package format
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"os"
+)
+
+// WriteToFile writes a raw dump of img to the file at path, creating
+// or truncating it as necessary. The dump consists of a header
+// containing the null-terminated name of the image's format followed
+// by the width, height, and stride of the image as little-endian
+// uint32s, after which the raw, tightly packed pixel data follows.
+// The format of img must implement fmt.Stringer.
+//
+// The resulting file can be read back in with [ReadFromFile].
+func (img *Image) WriteToFile(path string) (err error) {
+	name, ok := img.Format.(fmt.Stringer)
+	if !ok {
+		return errors.New("format has no name")
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create: %w", err)
+	}
+	defer func() {
+		cerr := file.Close()
+		if err == nil && cerr != nil {
+			err = fmt.Errorf("close: %w", cerr)
+		}
+	}()
+
+	w := bufio.NewWriter(file)
+	w.WriteString(name.String())
+	w.WriteByte(0)
+	binary.Write(w, binary.LittleEndian, [...]uint32{
+		uint32(img.Rect.Dx()),
+		uint32(img.Rect.Dy()),
+		uint32(img.rowBytes(img.Format.Size())),
+	})
+	for _, row := range img.Rows() {
+		w.Write(row)
+	}
+
+	err = w.Flush()
+	if err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+	return nil
+}
+
+// ReadFromFile reads a raw dump of an image written by
+// [Image.WriteToFile]. The bounds of the returned image always start
+// at the origin. An error is returned without allocating any pixel
+// data if the header describes more data than the file contains.
+func ReadFromFile(path string) (*Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("stat: %w", err)
+	}
+
+	r := bufio.NewReader(file)
+	name, err := r.ReadString(0)
+	if err != nil {
+		return nil, fmt.Errorf("read format name: %w", err)
+	}
+	f, ok := byName(name[:len(name)-1])
+	if !ok {
+		return nil, fmt.Errorf("unknown format %q", name[:len(name)-1])
+	}
+
+	var header [3]uint32
+	err = binary.Read(r, binary.LittleEndian, &header)
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	w, h, stride := int64(header[0]), int64(header[1]), int64(header[2])
+
+	rect := image.Rect(0, 0, int(w), int(h))
+	if tight := (&Image{Format: f, Rect: rect}).rowBytes(f.Size()); stride < int64(tight) {
+		return nil, fmt.Errorf("stride %v too small for width %v", stride, w)
+	}
+	remaining := info.Size() - int64(len(name)) - int64(binary.Size(header))
+	if (h > 0) && (stride > remaining/h) {
+		return nil, fmt.Errorf("%v rows with a stride of %v exceed the %v bytes of pixel data", h, stride, remaining)
+	}
+
+	img := newImage(f, rect)
+	row := make([]byte, stride)
+	for y, dst := range img.Rows() {
+		_, err := io.ReadFull(r, row)
+		if err != nil {
+			return nil, fmt.Errorf("read row %v: %w", y, err)
+		}
+		copy(dst, row)
+	}
+
+	return img, nil
+}
@@ -2,6 +2,8 @@ package format
 
 import (
 	"encoding/binary"
+	"errors"
+	"fmt"
 )
 
 // Format is a pixel format for a FormatImage and related types. This
@@ -18,16 +20,105 @@ type Format interface {
 	Write(buf []byte, r, g, b, a uint32)
 }
 
-// Various predefined Formats.
+// Various predefined Formats. Names follow the DRM convention of
+// describing the components of a little-endian word from most to
+// least significant, so, for example, XRGB8888 is stored in memory in
+// the byte order B, G, R, X and RGB888 in the byte order B, G, R.
 var (
 	ARGB8888 formatARGB8888
 	XRGB8888 formatXRGB8888
+	BGRX8888 formatBGRX8888
+	RGBX8888 formatRGBX8888
+	ABGR8888 formatABGR8888
+	RGB888   formatRGB888
+	RGB565   formatRGB565
+
+	ARGB2101010 formatARGB2101010
 )
 
+// Bitmap1 is a monochrome format with one bit per pixel, where a set
+// bit is white and a clear bit is black. Because multiple pixels are
+// packed into each byte, its Size method returns 0 and [Image] handles
+// it specially. Read and Write operate on the least significant bit of
+// the first byte of the provided slice.
+//
+// Functions and Image methods that step through raw data one whole
+// pixel at a time do not support Bitmap1. These are Cols, Subsample,
+// SubImage, TileInto, ResizeTo, ForEachPixel, MapPixels,
+// SaturatingAdd, and ConvertImage, which panic if given a Bitmap1
+// image, and Quantize, which returns an error.
+var Bitmap1 formatBitmap1
+
+// RGBA16 is a format with 16-bit, alpha-premultiplied components
+// stored in memory in the order R, G, B, A, each as a little-endian
+// uint16. Values are stored without any scaling, so conversions to and
+// from it are lossless.
+var RGBA16 formatRGBA16
+
+// A8 is an alpha-only format with a single 8-bit component, useful for
+// masks. It reads as white at the stored opacity, and writing discards
+// all color information.
+var A8 formatA8
+
+// predefined contains every predefined Format.
+var predefined = []Format{
+	ARGB8888,
+	XRGB8888,
+	BGRX8888,
+	RGBX8888,
+	Bitmap1,
+	ABGR8888,
+	RGB888,
+	RGB565,
+	RGBA16,
+	A8,
+	ARGB2101010,
+}
+
+// byName returns the predefined Format whose String method returns
+// name.
+func byName(name string) (Format, bool) {
+	for _, f := range predefined {
+		if s, ok := f.(fmt.Stringer); ok && (s.String() == name) {
+			return f, true
+		}
+	}
+	return nil, false
+}
+
+// ErrNoDRMFourCC is returned when there is no Format corresponding to
+// a DRM fourcc code.
+var ErrNoDRMFourCC = errors.New("no format for DRM fourcc code")
+
+// FormatFromDRMFourCC returns the predefined Format corresponding to
+// the DRM fourcc code, such as 0x34325241 for DRM_FORMAT_ARGB8888. If
+// there is no such format, it returns ErrNoDRMFourCC.
+//
+// Every predefined Format has a DRMFourCC method that returns its
+// code, or 0 if it has none.
+func FormatFromDRMFourCC(code uint32) (Format, error) {
+	if code == 0 {
+		return nil, ErrNoDRMFourCC
+	}
+
+	for _, f := range predefined {
+		if c, ok := f.(interface{ DRMFourCC() uint32 }); ok && (c.DRMFourCC() == code) {
+			return f, nil
+		}
+	}
+	return nil, ErrNoDRMFourCC
+}
+
+func fourcc(code string) uint32 {
+	return binary.LittleEndian.Uint32([]byte(code))
+}
+
 type formatARGB8888 struct{}
 
 func (formatARGB8888) String() string { return "ARGB8888" }
 
+func (formatARGB8888) DRMFourCC() uint32 { return fourcc("AR24") }
+
 func (formatARGB8888) Size() int { return 4 }
 
 func (formatARGB8888) Read(data []byte) (r, g, b, a uint32) {
@@ -56,6 +147,8 @@ type formatXRGB8888 struct{}
 
 func (formatXRGB8888) String() string { return "XRGB8888" }
 
+func (formatXRGB8888) DRMFourCC() uint32 { return fourcc("XR24") }
+
 func (formatXRGB8888) Size() int { return 4 }
 
 func (formatXRGB8888) Read(data []byte) (r, g, b, a uint32) {
@@ -74,3 +167,224 @@ func (formatXRGB8888) Write(buf []byte, r, g, b, a uint32) {
 	a = 0xFF << 24
 	binary.LittleEndian.PutUint32(buf, r|g|b|a)
 }
+
+type formatBGRX8888 struct{}
+
+func (formatBGRX8888) String() string { return "BGRX8888" }
+
+func (formatBGRX8888) DRMFourCC() uint32 { return fourcc("BX24") }
+
+func (formatBGRX8888) Size() int { return 4 }
+
+func (formatBGRX8888) Read(data []byte) (r, g, b, a uint32) {
+	n := binary.LittleEndian.Uint32(data)
+	a = 0xFFFF
+	b = (n >> 24 & 0xFF) * 0xFFFF / 0xFF
+	g = (n >> 16 & 0xFF) * 0xFFFF / 0xFF
+	r = (n >> 8 & 0xFF) * 0xFFFF / 0xFF
+	return
+}
+
+func (formatBGRX8888) Write(buf []byte, r, g, b, a uint32) {
+	b = (b * 0xFF / 0xFFFF) << 24
+	g = (g * 0xFF / 0xFFFF) << 16
+	r = (r * 0xFF / 0xFFFF) << 8
+	a = 0xFF
+	binary.LittleEndian.PutUint32(buf, r|g|b|a)
+}
+
+type formatRGBX8888 struct{}
+
+func (formatRGBX8888) String() string { return "RGBX8888" }
+
+func (formatRGBX8888) DRMFourCC() uint32 { return fourcc("RX24") }
+
+func (formatRGBX8888) Size() int { return 4 }
+
+func (formatRGBX8888) Read(data []byte) (r, g, b, a uint32) {
+	n := binary.LittleEndian.Uint32(data)
+	a = 0xFFFF
+	r = (n >> 24 & 0xFF) * 0xFFFF / 0xFF
+	g = (n >> 16 & 0xFF) * 0xFFFF / 0xFF
+	b = (n >> 8 & 0xFF) * 0xFFFF / 0xFF
+	return
+}
+
+func (formatRGBX8888) Write(buf []byte, r, g, b, a uint32) {
+	r = (r * 0xFF / 0xFFFF) << 24
+	g = (g * 0xFF / 0xFFFF) << 16
+	b = (b * 0xFF / 0xFFFF) << 8
+	a = 0xFF
+	binary.LittleEndian.PutUint32(buf, r|g|b|a)
+}
+
+type formatBitmap1 struct{}
+
+func (formatBitmap1) String() string { return "Bitmap1" }
+
+func (formatBitmap1) DRMFourCC() uint32 { return 0 }
+
+func (formatBitmap1) Size() int { return 0 }
+
+func (formatBitmap1) Read(data []byte) (r, g, b, a uint32) {
+	v := uint32(data[0]&1) * 0xFFFF
+	return v, v, v, 0xFFFF
+}
+
+func (formatBitmap1) Write(buf []byte, r, g, b, a uint32) {
+	y := (19595*r + 38470*g + 7471*b + 1<<15) >> 16
+	buf[0] = 0
+	if y >= 0x8000 {
+		buf[0] = 1
+	}
+}
+
+type formatABGR8888 struct{}
+
+func (formatABGR8888) String() string { return "ABGR8888" }
+
+func (formatABGR8888) DRMFourCC() uint32 { return fourcc("AB24") }
+
+func (formatABGR8888) Size() int { return 4 }
+
+func (formatABGR8888) Read(data []byte) (r, g, b, a uint32) {
+	a = uint32(data[3]) * 0xFFFF / 0xFF
+	r = uint32(data[0]) * a / 0xFF
+	g = uint32(data[1]) * a / 0xFF
+	b = uint32(data[2]) * a / 0xFF
+	return
+}
+
+func (formatABGR8888) Write(buf []byte, r, g, b, a uint32) {
+	if a == 0 {
+		copy(buf, []byte{0, 0, 0, 0})
+		return
+	}
+
+	buf[0] = byte(r * 0xFF / a)
+	buf[1] = byte(g * 0xFF / a)
+	buf[2] = byte(b * 0xFF / a)
+	buf[3] = byte(a * 0xFF / 0xFFFF)
+}
+
+type formatRGB888 struct{}
+
+func (formatRGB888) String() string { return "RGB888" }
+
+func (formatRGB888) DRMFourCC() uint32 { return fourcc("RG24") }
+
+func (formatRGB888) Size() int { return 3 }
+
+func (formatRGB888) Read(data []byte) (r, g, b, a uint32) {
+	a = 0xFFFF
+	b = uint32(data[0]) * 0xFFFF / 0xFF
+	g = uint32(data[1]) * 0xFFFF / 0xFF
+	r = uint32(data[2]) * 0xFFFF / 0xFF
+	return
+}
+
+func (formatRGB888) Write(buf []byte, r, g, b, a uint32) {
+	buf[0] = byte(b * 0xFF / 0xFFFF)
+	buf[1] = byte(g * 0xFF / 0xFFFF)
+	buf[2] = byte(r * 0xFF / 0xFFFF)
+}
+
+type formatRGB565 struct{}
+
+func (formatRGB565) String() string { return "RGB565" }
+
+func (formatRGB565) DRMFourCC() uint32 { return fourcc("RG16") }
+
+func (formatRGB565) Size() int { return 2 }
+
+func (formatRGB565) Read(data []byte) (r, g, b, a uint32) {
+	n := uint32(binary.LittleEndian.Uint16(data))
+	a = 0xFFFF
+	r = (n >> 11 & 0x1F) * 0xFFFF / 0x1F
+	g = (n >> 5 & 0x3F) * 0xFFFF / 0x3F
+	b = (n & 0x1F) * 0xFFFF / 0x1F
+	return
+}
+
+func (formatRGB565) Write(buf []byte, r, g, b, a uint32) {
+	r = (r*0x1F + 0x7FFF) / 0xFFFF << 11
+	g = (g*0x3F + 0x7FFF) / 0xFFFF << 5
+	b = (b*0x1F + 0x7FFF) / 0xFFFF
+	binary.LittleEndian.PutUint16(buf, uint16(r|g|b))
+}
+
+type formatRGBA16 struct{}
+
+func (formatRGBA16) String() string { return "RGBA16" }
+
+func (formatRGBA16) DRMFourCC() uint32 { return fourcc("AB48") }
+
+func (formatRGBA16) Size() int { return 8 }
+
+func (formatRGBA16) Read(data []byte) (r, g, b, a uint32) {
+	r = uint32(binary.LittleEndian.Uint16(data[0:]))
+	g = uint32(binary.LittleEndian.Uint16(data[2:]))
+	b = uint32(binary.LittleEndian.Uint16(data[4:]))
+	a = uint32(binary.LittleEndian.Uint16(data[6:]))
+	return
+}
+
+func (formatRGBA16) Write(buf []byte, r, g, b, a uint32) {
+	binary.LittleEndian.PutUint16(buf[0:], uint16(r))
+	binary.LittleEndian.PutUint16(buf[2:], uint16(g))
+	binary.LittleEndian.PutUint16(buf[4:], uint16(b))
+	binary.LittleEndian.PutUint16(buf[6:], uint16(a))
+}
+
+type formatA8 struct{}
+
+func (formatA8) String() string { return "A8" }
+
+func (formatA8) DRMFourCC() uint32 { return 0 }
+
+func (formatA8) Size() int { return 1 }
+
+func (formatA8) Read(data []byte) (r, g, b, a uint32) {
+	a = uint32(data[0]) * 0xFFFF / 0xFF
+	return a, a, a, a
+}
+
+func (formatA8) Write(buf []byte, r, g, b, a uint32) {
+	buf[0] = byte(a * 0xFF / 0xFFFF)
+}
+
+type formatARGB2101010 struct{}
+
+func (formatARGB2101010) String() string { return "ARGB2101010" }
+
+func (formatARGB2101010) DRMFourCC() uint32 { return fourcc("AR30") }
+
+func (formatARGB2101010) Size() int { return 4 }
+
+func (formatARGB2101010) Read(data []byte) (r, g, b, a uint32) {
+	n := binary.LittleEndian.Uint32(data)
+	a = (n >> 30) * 0x5555
+	r = expand10(n>>20&0x3FF) * a / 0xFFFF
+	g = expand10(n>>10&0x3FF) * a / 0xFFFF
+	b = expand10(n&0x3FF) * a / 0xFFFF
+	return
+}
+
+func (formatARGB2101010) Write(buf []byte, r, g, b, a uint32) {
+	if a == 0 {
+		binary.LittleEndian.PutUint32(buf, 0)
+		return
+	}
+
+	r = min(r*0xFFFF/a, 0xFFFF) >> 6 << 20
+	g = min(g*0xFFFF/a, 0xFFFF) >> 6 << 10
+	b = min(b*0xFFFF/a, 0xFFFF) >> 6
+	a = (a >> 14) << 30
+	binary.LittleEndian.PutUint32(buf, r|g|b|a)
+}
+
+// expand10 expands a 10-bit value to 16 bits, replicating the high
+// bits into the low bits so that the maximum value maps to 0xFFFF.
+func expand10(v uint32) uint32 {
+	return v<<6 | v>>4
+}
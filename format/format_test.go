@@ -1,6 +1,15 @@
 package format_test
 
 import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"io"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"deedles.dev/ximage/format"
@@ -18,3 +27,757 @@ func TestFormat(t *testing.T) {
 	require.Equal(t, uint32(0x3333), b)
 	require.Equal(t, uint32(0xFFFF), a)
 }
+
+func TestWriteTransparent(t *testing.T) {
+	data := [...]byte{0x33, 0x22, 0x11, 0xFF}
+	format.ARGB8888.Write(data[:], 0, 0, 0, 0)
+	require.Equal(t, [4]byte{}, data)
+
+	formats := []format.Format{
+		format.ARGB8888,
+		format.ABGR8888,
+		format.ARGB2101010,
+		format.RGBA16,
+		format.A8,
+	}
+	for _, f := range formats {
+		t.Run(fmt.Sprint(f), func(t *testing.T) {
+			img := format.NewImage(f, image.Rect(0, 0, 2, 2))
+			img.Fill(img.Rect, color.White)
+			img.Set(1, 1, color.Transparent)
+			require.Equal(t, color.RGBA64{}, img.RGBA64At(1, 1))
+		})
+	}
+}
+
+func TestRows(t *testing.T) {
+	img := format.Image{
+		Format: format.ARGB8888,
+		Rect:   image.Rect(1, 2, 4, 6),
+		Pix:    make([]byte, 3*4*4),
+	}
+
+	var n int
+	for y, row := range img.Rows() {
+		require.Equal(t, 2+n, y)
+		require.Len(t, row, 3*4)
+		require.Same(t, &img.Pix[img.PixOffset(1, y)], &row[0])
+		n++
+	}
+	require.Equal(t, 4, n)
+
+	for y, row := range img.Rows() {
+		if y == 3 {
+			format.ARGB8888.Write(row[4:], 0, 0, 0xFFFF, 0xFFFF)
+		}
+	}
+	_, _, b, a := img.At(2, 3).RGBA()
+	require.Equal(t, uint32(0xFFFF), b)
+	require.Equal(t, uint32(0xFFFF), a)
+}
+
+func TestCols(t *testing.T) {
+	img := format.Image{
+		Format: format.ARGB8888,
+		Rect:   image.Rect(0, 0, 3, 2),
+		Pix:    make([]byte, 3*2*4),
+	}
+	img.Set(0, 0, color.RGBA{0xFF, 0, 0, 0xFF})
+	img.Set(0, 1, color.RGBA{0, 0xFF, 0, 0xFF})
+
+	var n int
+	for x, col := range img.Cols() {
+		require.Equal(t, n, x)
+		require.Len(t, col, 2*4)
+		if x == 0 {
+			require.Equal(t, []byte{0, 0, 0xFF, 0xFF, 0, 0xFF, 0, 0xFF}, col)
+		}
+		n++
+	}
+	require.Equal(t, 3, n)
+}
+
+func TestWriteToFile(t *testing.T) {
+	img := format.Image{
+		Format: format.XRGB8888,
+		Rect:   image.Rect(0, 0, 3, 2),
+		Pix:    make([]byte, 3*2*4),
+	}
+	img.Set(1, 1, color.RGBA{0x11, 0x22, 0x33, 0xFF})
+
+	path := filepath.Join(t.TempDir(), "dump")
+	require.Nil(t, img.WriteToFile(path))
+
+	read, err := format.ReadFromFile(path)
+	require.Nil(t, err)
+	require.Equal(t, &img, read)
+
+	for _, header := range [][3]uint32{
+		{0xFFFFFFFF, 0xFFFFFFFF, 0xFFFFFFFF},
+		{3, 2, 0xFFFFFFFF},
+		{3, 3, 12},
+		{4, 2, 12},
+	} {
+		data := binary.LittleEndian.AppendUint32([]byte("XRGB8888\x00"), header[0])
+		data = binary.LittleEndian.AppendUint32(data, header[1])
+		data = binary.LittleEndian.AppendUint32(data, header[2])
+		data = append(data, make([]byte, 3*2*4)...)
+		require.Nil(t, os.WriteFile(path, data, 0600))
+
+		_, err := format.ReadFromFile(path)
+		require.NotNil(t, err, header)
+	}
+}
+
+func TestSaturatingAdd(t *testing.T) {
+	dst := format.Image{
+		Format: format.XRGB8888,
+		Rect:   image.Rect(0, 0, 2, 2),
+		Pix:    make([]byte, 2*2*4),
+	}
+	dst.Set(1, 1, color.RGBA{0xC0, 0x10, 0, 0xFF})
+
+	src := format.Image{
+		Format: format.XRGB8888,
+		Rect:   image.Rect(0, 0, 1, 1),
+		Pix:    make([]byte, 4),
+	}
+	format.SaturatingAdd(&dst, &src, image.Pt(1, 1))
+	require.Equal(t, []byte{0, 0x10, 0xC0, 0xFF}, dst.Pix[12:])
+
+	src.Set(0, 0, color.RGBA{0xC0, 0x20, 0x30, 0xFF})
+	format.SaturatingAdd(&dst, &src, image.Pt(1, 1))
+	require.Equal(t, []byte{0x30, 0x30, 0xFF, 0xFF}, dst.Pix[12:])
+	require.Equal(t, make([]byte, 12), dst.Pix[:12])
+}
+
+func TestQuantize(t *testing.T) {
+	img := format.Image{
+		Format: format.ARGB8888,
+		Rect:   image.Rect(0, 0, 8, 8),
+		Pix:    make([]byte, 8*8*4),
+	}
+	for y := range 8 {
+		for x := range 8 {
+			img.Set(x, y, color.RGBA{uint8(x * 32), uint8(y * 32), 0x80, 0xFF})
+		}
+	}
+
+	p, err := img.Quantize(4)
+	require.Nil(t, err)
+	require.LessOrEqual(t, len(p.Palette), 4)
+	require.Equal(t, img.Rect, p.Rect)
+	for _, i := range p.Pix {
+		require.Less(t, int(i), len(p.Palette))
+	}
+
+	for y := range 8 {
+		for x := range 8 {
+			src := img.At(x, y)
+			require.Equal(t, p.Palette.Convert(src), p.At(x, y), "(%v, %v)", x, y)
+		}
+	}
+
+	_, err = img.Quantize(0)
+	require.NotNil(t, err)
+}
+
+func TestQuantizeExact(t *testing.T) {
+	img := format.NewImage(format.ARGB8888, image.Rect(0, 0, 4, 4))
+	for y := range 4 {
+		for x := range 4 {
+			img.Set(x, y, color.RGBA{uint8(x * 64), uint8(y * 64), 0x80, 0xFF})
+		}
+	}
+
+	p, err := img.Quantize(16)
+	require.Nil(t, err)
+	require.Len(t, p.Palette, 16)
+	for y := range 4 {
+		for x := range 4 {
+			require.Equal(t, color.RGBA64Model.Convert(img.At(x, y)), p.At(x, y), "(%v, %v)", x, y)
+		}
+	}
+}
+
+func TestSubsample(t *testing.T) {
+	img := format.Image{
+		Format: format.XRGB8888,
+		Rect:   image.Rect(0, 0, 4, 4),
+		Pix:    make([]byte, 4*4*4),
+	}
+	for y := range 4 {
+		for x := range 4 {
+			img.Set(x, y, color.RGBA{uint8(x), uint8(y), 0, 0xFF})
+		}
+	}
+
+	sub := img.Subsample(2, 2)
+	require.Equal(t, image.Rect(0, 0, 2, 2), sub.Rect)
+	for y := range 2 {
+		for x := range 2 {
+			require.Equal(t, img.At(x*2, y*2), sub.At(x, y))
+		}
+	}
+}
+
+func TestResizeTo(t *testing.T) {
+	img := format.Image{
+		Format: format.ARGB8888,
+		Rect:   image.Rect(0, 0, 3, 3),
+		Pix:    make([]byte, 3*3*4),
+	}
+	c := color.RGBA{0x40, 0x20, 0x10, 0xFF}
+	for y := range 3 {
+		for x := range 3 {
+			img.Set(x, y, c)
+		}
+	}
+
+	for _, size := range []image.Point{{1, 1}, {3, 3}, {7, 5}, {16, 2}} {
+		dst := img.ResizeTo(size.X, size.Y)
+		require.Equal(t, size, dst.Rect.Size())
+		for y := range size.Y {
+			for x := range size.X {
+				require.Equal(t, img.At(0, 0), dst.At(x, y))
+			}
+		}
+	}
+}
+
+func TestCountColors(t *testing.T) {
+	img := format.Image{
+		Format: format.ARGB8888,
+		Rect:   image.Rect(0, 0, 4, 4),
+		Pix:    make([]byte, 4*4*4),
+	}
+	require.Equal(t, 1, img.CountColors())
+
+	for y := range 4 {
+		for x := range 4 {
+			img.Set(x, y, color.RGBA{uint8(x * 10), 0, 0, 0xFF})
+		}
+	}
+	require.Equal(t, 4, img.CountColors())
+	require.Equal(t, 2, img.CountColorsMax(2))
+	require.Equal(t, 4, img.CountColorsMax(10))
+}
+
+func TestTileInto(t *testing.T) {
+	img := format.Image{
+		Format: format.ARGB8888,
+		Rect:   image.Rect(0, 0, 64, 64),
+		Pix:    make([]byte, 64*64*4),
+	}
+
+	tiles := img.TileInto(4, 4)
+	require.Len(t, tiles, 16)
+	for i, tile := range tiles {
+		row, col := i/4, i%4
+		require.Equal(t, image.Rect(col*16, row*16, col*16+16, row*16+16), tile.Rect)
+	}
+
+	c := color.RGBA{0x10, 0x20, 0x30, 0xFF}
+	tiles[6].Set(37, 20, c)
+	require.Equal(t, tiles[6].At(37, 20), img.At(37, 20))
+	require.Equal(t, img.Stride(), tiles[6].Stride())
+
+	odd := format.NewImage(format.ARGB8888, image.Rect(1, 2, 11, 9))
+	tiles = odd.TileInto(3, 2)
+	require.Len(t, tiles, 6)
+	require.Equal(t, image.Rect(1, 2, 4, 5), tiles[0].Rect)
+	require.Equal(t, image.Rect(7, 5, 10, 8), tiles[5].Rect)
+
+	require.Panics(t, func() { img.TileInto(0, 4) })
+	require.Panics(t, func() { img.TileInto(4, -1) })
+	require.Panics(t, func() { img.TileInto(65, 1) })
+	require.Panics(t, func() { odd.TileInto(1, 8) })
+}
+
+func TestIsZero(t *testing.T) {
+	img := format.Image{
+		Format: format.ARGB8888,
+		Rect:   image.Rect(0, 0, 4, 4),
+		Pix:    make([]byte, 4*4*4),
+	}
+	require.True(t, img.IsZero())
+
+	img.Pix[len(img.Pix)-1] = 1
+	require.False(t, img.IsZero())
+
+	img.Pix = append(make([]byte, 4*4*4), 1)
+	require.True(t, img.IsZero())
+}
+
+func TestOpaqueFormats(t *testing.T) {
+	tests := []struct {
+		format   format.Format
+		expected [4]byte
+	}{
+		{format.XRGB8888, [...]byte{0x33, 0x22, 0x11, 0xFF}},
+		{format.BGRX8888, [...]byte{0xFF, 0x11, 0x22, 0x33}},
+		{format.RGBX8888, [...]byte{0xFF, 0x33, 0x22, 0x11}},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprint(test.format), func(t *testing.T) {
+			var data [4]byte
+			test.format.Write(data[:], 0x1111, 0x2222, 0x3333, 0xFFFF)
+			require.Equal(t, test.expected, data)
+
+			r, g, b, a := test.format.Read(data[:])
+			require.Equal(t, uint32(0x1111), r)
+			require.Equal(t, uint32(0x2222), g)
+			require.Equal(t, uint32(0x3333), b)
+			require.Equal(t, uint32(0xFFFF), a)
+		})
+	}
+}
+
+func TestRotate180(t *testing.T) {
+	img := format.Image{
+		Format: format.XRGB8888,
+		Rect:   image.Rect(1, 1, 3, 3),
+		Pix:    make([]byte, 2*2*4),
+	}
+	corners := []color.RGBA{
+		{0xFF, 0, 0, 0xFF},
+		{0, 0xFF, 0, 0xFF},
+		{0, 0, 0xFF, 0xFF},
+		{0xFF, 0xFF, 0xFF, 0xFF},
+	}
+	img.Set(1, 1, corners[0])
+	img.Set(2, 1, corners[1])
+	img.Set(1, 2, corners[2])
+	img.Set(2, 2, corners[3])
+
+	rot := img.Rotate180()
+	require.Equal(t, img.Rect, rot.Rect)
+	require.Equal(t, img.At(1, 1), rot.At(2, 2))
+	require.Equal(t, img.At(2, 1), rot.At(1, 2))
+	require.Equal(t, img.At(1, 2), rot.At(2, 1))
+	require.Equal(t, img.At(2, 2), rot.At(1, 1))
+
+	require.Equal(t, &img, rot.Rotate180())
+}
+
+func TestBitmap1(t *testing.T) {
+	img := format.Image{
+		Format: format.Bitmap1,
+		Rect:   image.Rect(0, 0, 16, 8),
+		Pix:    make([]byte, 16),
+	}
+	require.Equal(t, 2, img.Stride())
+
+	img.Set(0, 0, color.White)
+	img.Set(9, 3, color.White)
+	img.Set(15, 7, color.White)
+	require.Equal(t, byte(0x80), img.Pix[0])
+	require.Equal(t, byte(0x40), img.Pix[7])
+	require.Equal(t, byte(0x01), img.Pix[15])
+
+	i, bit := img.BitOffset(9, 3)
+	require.Equal(t, 7, i)
+	require.Equal(t, 6, bit)
+	require.Equal(t, 7*8+1, img.PixOffset(9, 3))
+
+	require.Equal(t, color.Gray16Model.Convert(color.White), color.Gray16Model.Convert(img.At(9, 3)))
+	require.Equal(t, color.Gray16Model.Convert(color.Black), color.Gray16Model.Convert(img.At(8, 3)))
+
+	img.Set(9, 3, color.Black)
+	require.Equal(t, byte(0), img.Pix[7])
+
+	var rows int
+	for _, row := range img.Rows() {
+		require.Len(t, row, 2)
+		rows++
+	}
+	require.Equal(t, 8, rows)
+
+	rot := img.Rotate180()
+	require.Equal(t, byte(0x80), rot.Pix[0])
+	require.Equal(t, byte(0x01), rot.Pix[15])
+	require.Equal(t, 2, rot.CountColors())
+	require.Equal(t, img.Pix, rot.Rotate180().Pix)
+
+	odd := format.NewImage(format.Bitmap1, image.Rect(2, 1, 5, 3))
+	odd.Set(2, 1, color.White)
+	odd.Set(3, 2, color.White)
+	rot = odd.Rotate180()
+	require.Equal(t, []byte{0x40, 0x20}, rot.Pix)
+
+	argb := format.NewImage(format.ARGB8888, img.Rect)
+	require.Panics(t, func() { img.Cols() })
+	require.Panics(t, func() { img.Subsample(2, 2) })
+	require.Panics(t, func() { img.SubImage(image.Rect(8, 0, 16, 8)) })
+	require.Panics(t, func() { img.TileInto(2, 2) })
+	require.Panics(t, func() { img.ResizeTo(8, 4) })
+	require.Panics(t, func() { format.SaturatingAdd(&img, argb, image.Point{}) })
+	require.Panics(t, func() { format.SaturatingAdd(argb, &img, image.Point{}) })
+	require.Panics(t, func() { format.ConvertImage(&img, argb) })
+	require.Panics(t, func() { format.ConvertImage(argb, &img) })
+	_, err := img.Quantize(2)
+	require.NotNil(t, err)
+}
+
+func TestABGR8888(t *testing.T) {
+	tests := []struct {
+		r, g, b, a uint32
+		expected   [4]byte
+	}{
+		{0, 0, 0, 0, [...]byte{0, 0, 0, 0}},
+		{0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF, [...]byte{0xFF, 0xFF, 0xFF, 0xFF}},
+		{0x1111, 0x2222, 0x3333, 0xFFFF, [...]byte{0x11, 0x22, 0x33, 0xFF}},
+		{0, 0, 0, 0xFFFF, [...]byte{0, 0, 0, 0xFF}},
+	}
+	for _, test := range tests {
+		var data [4]byte
+		format.ABGR8888.Write(data[:], test.r, test.g, test.b, test.a)
+		require.Equal(t, test.expected, data)
+
+		r, g, b, a := format.ABGR8888.Read(data[:])
+		require.Equal(t, [...]uint32{test.r, test.g, test.b, test.a}, [...]uint32{r, g, b, a})
+	}
+}
+
+func TestRGB888(t *testing.T) {
+	require.Equal(t, 3, format.RGB888.Size())
+	require.Equal(t, "RGB888", fmt.Sprint(format.RGB888))
+
+	var data [3]byte
+	format.RGB888.Write(data[:], 0x1111, 0x2222, 0x3333, 0x8000)
+	require.Equal(t, [...]byte{0x33, 0x22, 0x11}, data)
+	r, g, b, a := format.RGB888.Read(data[:])
+	require.Equal(t, [...]uint32{0x1111, 0x2222, 0x3333, 0xFFFF}, [...]uint32{r, g, b, a})
+}
+
+func TestRGB565(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      [3]uint32
+		encoded [2]byte
+		out     [3]uint32
+	}{
+		{"Min", [3]uint32{0, 0, 0}, [...]byte{0, 0}, [3]uint32{0, 0, 0}},
+		{"Max", [3]uint32{0xFFFF, 0xFFFF, 0xFFFF}, [...]byte{0xFF, 0xFF}, [3]uint32{0xFFFF, 0xFFFF, 0xFFFF}},
+		{"Mid", [3]uint32{0x8000, 0x8000, 0x8000}, [...]byte{0x10, 0x84}, [3]uint32{0x8420, 0x8207, 0x8420}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, 2, format.RGB565.Size())
+
+			var data [2]byte
+			format.RGB565.Write(data[:], test.in[0], test.in[1], test.in[2], 0xFFFF)
+			require.Equal(t, test.encoded, data)
+
+			r, g, b, a := format.RGB565.Read(data[:])
+			require.Equal(t, test.out, [...]uint32{r, g, b})
+			require.Equal(t, uint32(0xFFFF), a)
+		})
+	}
+}
+
+func TestNewImage(t *testing.T) {
+	img := format.NewImage(format.ARGB8888, image.Rect(1, 2, 5, 5))
+	require.Equal(t, image.Rect(1, 2, 5, 5), img.Rect)
+	require.Len(t, img.Pix, 4*3*4)
+	require.True(t, img.IsZero())
+
+	bitmap := format.NewImage(format.Bitmap1, image.Rect(0, 0, 10, 2))
+	require.Len(t, bitmap.Pix, 4)
+
+	require.Panics(t, func() { format.NewImage(format.ARGB8888, image.Rectangle{}) })
+}
+
+func TestSubImage(t *testing.T) {
+	img := format.NewImage(format.ARGB8888, image.Rect(0, 0, 8, 8))
+	sub := img.SubImage(image.Rect(2, 3, 12, 5))
+	require.Equal(t, image.Rect(2, 3, 8, 5), sub.Rect)
+	require.Equal(t, img.Stride(), sub.Stride())
+
+	c := color.RGBA{0x10, 0x20, 0x30, 0xFF}
+	sub.Set(4, 4, c)
+	require.Equal(t, sub.At(4, 4), img.At(4, 4))
+	require.Same(t, &img.Pix[img.PixOffset(2, 3)], &sub.Pix[sub.PixOffset(2, 3)])
+
+	require.Nil(t, img.SubImage(image.Rect(10, 10, 20, 20)))
+}
+
+func TestNewImageWithStride(t *testing.T) {
+	img, err := format.NewImageWithStride(format.XRGB8888, image.Rect(0, 0, 3, 2), 16)
+	require.Nil(t, err)
+	require.Equal(t, 16, img.Stride())
+	require.Len(t, img.Pix, 32)
+	require.Equal(t, 16+8, img.PixOffset(2, 1))
+
+	img.Set(2, 1, color.RGBA{0x11, 0x22, 0x33, 0xFF})
+	require.Equal(t, []byte{0x33, 0x22, 0x11, 0xFF}, img.Pix[24:28])
+
+	_, err = format.NewImageWithStride(format.XRGB8888, image.Rect(0, 0, 3, 2), 8)
+	require.NotNil(t, err)
+}
+
+func TestConvertImage(t *testing.T) {
+	src := format.NewImage(format.ARGB8888, image.Rect(1, 1, 4, 3))
+	src.Set(2, 2, color.RGBA{0x11, 0x22, 0x33, 0xFF})
+
+	dst := format.NewImage(format.ABGR8888, src.Rect)
+	format.ConvertImage(dst, src)
+	require.Equal(t, []byte{0x11, 0x22, 0x33, 0xFF}, dst.Pix[dst.PixOffset(2, 2):][:4])
+	for y := src.Rect.Min.Y; y < src.Rect.Max.Y; y++ {
+		for x := src.Rect.Min.X; x < src.Rect.Max.X; x++ {
+			require.Equal(t, color.RGBA64Model.Convert(src.At(x, y)), color.RGBA64Model.Convert(dst.At(x, y)))
+		}
+	}
+
+	require.Panics(t, func() { format.ConvertImage(format.NewImage(format.ABGR8888, image.Rect(0, 0, 3, 2)), src) })
+}
+
+func BenchmarkConvertImage(b *testing.B) {
+	src := format.NewImage(format.ARGB8888, image.Rect(0, 0, 256, 256))
+	dst := format.NewImage(format.ABGR8888, src.Rect)
+
+	b.Run("ConvertImage", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			format.ConvertImage(dst, src)
+		}
+	})
+
+	b.Run("AtSet", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			for y := src.Rect.Min.Y; y < src.Rect.Max.Y; y++ {
+				for x := src.Rect.Min.X; x < src.Rect.Max.X; x++ {
+					dst.Set(x, y, src.At(x, y))
+				}
+			}
+		}
+	})
+}
+
+func TestFill(t *testing.T) {
+	img := format.NewImage(format.XRGB8888, image.Rect(0, 0, 5, 4))
+	img.Fill(image.Rect(1, 1, 10, 3), color.RGBA{0x11, 0x22, 0x33, 0xFF})
+	for y := range 4 {
+		for x := range 5 {
+			expected := []byte{0, 0, 0, 0}
+			if (x >= 1) && (y >= 1) && (y < 3) {
+				expected = []byte{0x33, 0x22, 0x11, 0xFF}
+			}
+			require.Equal(t, expected, img.Pix[img.PixOffset(x, y):][:4], "(%v, %v)", x, y)
+		}
+	}
+	require.False(t, img.IsZero())
+
+	img.Clear()
+	require.True(t, img.IsZero())
+}
+
+func TestDraw(t *testing.T) {
+	src := image.NewUniform(color.RGBA{0x11, 0x22, 0x33, 0xFF})
+	dst := format.NewImage(format.ARGB8888, image.Rect(0, 0, 4, 4))
+	draw.Draw(dst, image.Rect(1, 1, 3, 3), src, image.Point{}, draw.Src)
+
+	require.Equal(t, color.RGBA64Model.Convert(src.C), color.RGBA64Model.Convert(dst.At(2, 2)))
+	require.Equal(t, []byte{0, 0, 0, 0}, dst.Pix[dst.PixOffset(0, 0):][:4])
+}
+
+func TestRGBA64At(t *testing.T) {
+	for _, f := range []format.Format{format.ARGB8888, format.ABGR8888} {
+		t.Run(fmt.Sprint(f), func(t *testing.T) {
+			img := format.NewImage(f, image.Rect(0, 0, 2, 2))
+			c := color.RGBA64{0x1111, 0x2222, 0x3333, 0xFFFF}
+			img.Set(1, 0, c)
+			require.Equal(t, c, img.RGBA64At(1, 0))
+			require.Equal(t, color.RGBA64{}, img.RGBA64At(0, 0))
+			require.Equal(t, color.RGBA64{}, img.RGBA64At(5, 5))
+		})
+	}
+}
+
+func TestRow(t *testing.T) {
+	img, err := format.NewImageWithStride(format.XRGB8888, image.Rect(0, 2, 3, 5), 16)
+	require.Nil(t, err)
+
+	row := img.Row(3)
+	require.Len(t, row, 12)
+	require.Same(t, &img.Pix[16], &row[0])
+
+	require.Nil(t, img.Row(1))
+	require.Nil(t, img.Row(5))
+}
+
+func TestForEachPixel(t *testing.T) {
+	img := format.NewImage(format.XRGB8888, image.Rect(1, 1, 3, 3))
+
+	var visited []image.Point
+	img.ForEachPixel(func(x, y int, pix []byte) {
+		visited = append(visited, image.Pt(x, y))
+		pix[0] = byte(x*10 + y)
+	})
+	require.Equal(t, []image.Point{{1, 1}, {2, 1}, {1, 2}, {2, 2}}, visited)
+	require.Equal(t, byte(21), img.Pix[img.PixOffset(2, 1)])
+
+	inverted := img.MapPixels(func(x, y int, src []byte) (r, g, b, a uint32) {
+		r, g, b, a = format.XRGB8888.Read(src)
+		return 0xFFFF - r, 0xFFFF - g, 0xFFFF - b, a
+	})
+	require.Equal(t, img.Rect, inverted.Rect)
+	require.Equal(t, []byte{0xFF - 21, 0xFF, 0xFF, 0xFF}, inverted.Pix[inverted.PixOffset(2, 1):][:4])
+}
+
+func TestWriteToReadFrom(t *testing.T) {
+	img, err := format.NewImageWithStride(format.XRGB8888, image.Rect(0, 0, 2, 2), 12)
+	require.Nil(t, err)
+	img.Set(0, 0, color.RGBA{1, 2, 3, 0xFF})
+	img.Set(1, 1, color.RGBA{4, 5, 6, 0xFF})
+
+	var buf bytes.Buffer
+	n, err := img.WriteTo(&buf)
+	require.Nil(t, err)
+	require.Equal(t, int64(16), n)
+	require.Equal(t, []byte{3, 2, 1, 0xFF, 0, 0, 0, 0, 0, 0, 0, 0, 6, 5, 4, 0xFF}, buf.Bytes())
+
+	read := format.NewImage(format.XRGB8888, img.Rect)
+	n, err = read.ReadFrom(bytes.NewReader(buf.Bytes()))
+	require.Nil(t, err)
+	require.Equal(t, int64(16), n)
+	require.Equal(t, buf.Bytes(), read.Pix)
+
+	_, err = read.ReadFrom(bytes.NewReader(buf.Bytes()[:10]))
+	require.Equal(t, io.ErrUnexpectedEOF, err)
+}
+
+func TestFromStdImage(t *testing.T) {
+	c := color.NRGBA{0x10, 0x20, 0x30, 0xFF}
+	rgba := image.NewRGBA(image.Rect(1, 1, 3, 3))
+	rgba.Set(2, 2, c)
+	nrgba := image.NewNRGBA(rgba.Rect)
+	nrgba.Set(2, 2, c)
+	rgba64 := image.NewRGBA64(rgba.Rect)
+	rgba64.Set(2, 2, c)
+	gray := image.NewGray(rgba.Rect)
+	gray.Set(2, 2, color.White)
+
+	for _, src := range []image.Image{rgba, nrgba, rgba64, gray} {
+		t.Run(fmt.Sprintf("%T", src), func(t *testing.T) {
+			img := format.FromStdImage(src, format.ABGR8888)
+			require.Equal(t, src.Bounds(), img.Rect)
+			for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+				for x := img.Rect.Min.X; x < img.Rect.Max.X; x++ {
+					require.Equal(t, color.RGBA64Model.Convert(src.At(x, y)), img.RGBA64At(x, y))
+				}
+			}
+		})
+	}
+
+	argb := format.AsARGB8888(nrgba)
+	require.Same(t, &nrgba.Pix[0], &argb.Pix[0])
+	require.Equal(t, color.RGBA64Model.Convert(c), argb.RGBA64At(2, 2))
+}
+
+func TestBitmap1Conversion(t *testing.T) {
+	src := image.NewGray(image.Rect(0, 0, 3, 1))
+	src.Set(1, 0, color.White)
+
+	img := format.FromStdImage(src, format.Bitmap1)
+	require.Equal(t, []byte{0x40}, img.Pix)
+	require.Equal(t, 2, img.CountColors())
+}
+
+func TestToStdImage(t *testing.T) {
+	img := format.NewImage(format.ARGB8888, image.Rect(0, 0, 2, 2))
+	img.Set(1, 0, color.RGBA{0x40, 0x20, 0x10, 0x80})
+	img.Set(0, 1, color.RGBA{0x11, 0x22, 0x33, 0xFF})
+
+	rgba := format.ToStdImage(img)
+	require.Equal(t, img.Rect, rgba.Rect)
+	for y := range 2 {
+		for x := range 2 {
+			require.Equal(t, color.RGBAModel.Convert(img.At(x, y)), rgba.At(x, y))
+		}
+	}
+
+	abgr := format.NewImage(format.ABGR8888, image.Rect(0, 0, 2, 2))
+	abgr.Set(0, 1, color.RGBA{0x11, 0x22, 0x33, 0xFF})
+	abgr.Set(1, 1, color.RGBA{0x10, 0x10, 0x10, 0x80})
+	copied := format.ToStdImage(abgr)
+	require.NotSame(t, &abgr.Pix[0], &copied.Pix[0])
+	require.Equal(t, color.RGBA{0x11, 0x22, 0x33, 0xFF}, copied.At(0, 1))
+	require.Equal(t, color.RGBAModel.Convert(abgr.At(1, 1)), copied.At(1, 1))
+
+	copied.Pix[0] = 0xFF
+	require.Zero(t, abgr.Pix[0])
+}
+
+func TestRGBA16(t *testing.T) {
+	require.Equal(t, 8, format.RGBA16.Size())
+
+	for _, v := range []uint32{0, 0x8000, 0xFFFF} {
+		var data [8]byte
+		format.RGBA16.Write(data[:], v, v/2, v/4, v)
+		require.Equal(t, byte(v), data[0])
+		require.Equal(t, byte(v>>8), data[1])
+
+		r, g, b, a := format.RGBA16.Read(data[:])
+		require.Equal(t, [...]uint32{v, v / 2, v / 4, v}, [...]uint32{r, g, b, a})
+	}
+}
+
+func TestA8(t *testing.T) {
+	require.Equal(t, 1, format.A8.Size())
+
+	var data [1]byte
+	format.A8.Write(data[:], 0x1111, 0x2222, 0x3333, 0x8080)
+	require.Equal(t, byte(0x80), data[0])
+
+	r, g, b, a := format.A8.Read(data[:])
+	require.Equal(t, [...]uint32{0x8080, 0x8080, 0x8080, 0x8080}, [...]uint32{r, g, b, a})
+}
+
+func TestARGB2101010(t *testing.T) {
+	for i, expected := range []uint32{0, 0x5555, 0xAAAA, 0xFFFF} {
+		data := binary.LittleEndian.AppendUint32(nil, uint32(i)<<30|0x3FFFFFFF)
+		r, g, b, a := format.ARGB2101010.Read(data)
+		require.Equal(t, expected, a)
+		require.Equal(t, [...]uint32{a, a, a}, [...]uint32{r, g, b})
+	}
+
+	var data [4]byte
+	format.ARGB2101010.Write(data[:], 0xFFFF, 0x8000, 0, 0xFFFF)
+	require.Equal(t, uint32(0xFFF00000|0x200<<10), binary.LittleEndian.Uint32(data[:]))
+	r, g, b, a := format.ARGB2101010.Read(data[:])
+	require.Equal(t, [...]uint32{0xFFFF, 0x8020, 0, 0xFFFF}, [...]uint32{r, g, b, a})
+
+	format.ARGB2101010.Write(data[:], 0, 0, 0, 0)
+	require.Equal(t, [4]byte{}, data)
+}
+
+func TestDRMFourCC(t *testing.T) {
+	require.Equal(t, uint32(0x34325241), format.ARGB8888.DRMFourCC())
+	require.Equal(t, uint32(0x34325258), format.XRGB8888.DRMFourCC())
+	require.Equal(t, uint32(0x34324241), format.ABGR8888.DRMFourCC())
+	require.Zero(t, format.A8.DRMFourCC())
+
+	formats := []format.Format{
+		format.ARGB8888,
+		format.XRGB8888,
+		format.BGRX8888,
+		format.RGBX8888,
+		format.ABGR8888,
+		format.RGB888,
+		format.RGB565,
+		format.ARGB2101010,
+	}
+	for _, f := range formats {
+		code := f.(interface{ DRMFourCC() uint32 }).DRMFourCC()
+		found, err := format.FormatFromDRMFourCC(code)
+		require.Nil(t, err)
+		require.Equal(t, f, found)
+	}
+
+	_, err := format.FormatFromDRMFourCC(0)
+	require.Equal(t, format.ErrNoDRMFourCC, err)
+	_, err = format.FormatFromDRMFourCC(0x12345678)
+	require.Equal(t, format.ErrNoDRMFourCC, err)
+}
@@ -1,8 +1,13 @@
 package format
 
 import (
+	"bytes"
+	"fmt"
 	"image"
 	"image/color"
+	"image/draw"
+	"io"
+	"iter"
 )
 
 // Model implements color.Model using a Format.
@@ -26,9 +31,11 @@ type Color struct {
 	Data [8]byte
 }
 
-// Slice returns a slice of Data correctly sized for the color's format.
+// Slice returns a slice of Data correctly sized for the color's
+// format. Formats with a size of zero, such as [Bitmap1], use a single
+// byte.
 func (c *Color) Slice() []byte {
-	size := c.Format.Size()
+	size := max(c.Format.Size(), 1)
 	return c.slice(size)
 }
 
@@ -40,11 +47,59 @@ func (c *Color) RGBA() (r, g, b, a uint32) {
 	return c.Format.Read(c.Slice())
 }
 
+var (
+	_ draw.Image        = (*Image)(nil)
+	_ image.RGBA64Image = (*Image)(nil)
+	_ io.WriterTo       = (*Image)(nil)
+	_ io.ReaderFrom     = (*Image)(nil)
+)
+
 // Image is an image with a color format defined by Format.
 type Image struct {
 	Format Format
 	Rect   image.Rectangle
 	Pix    []byte
+
+	// Pitch is the number of bytes between the starts of vertically
+	// adjacent pixels. If it is zero, rows are assumed to be tightly
+	// packed.
+	Pitch int
+}
+
+// NewImage returns a new image with the given format and bounds. The
+// pixel data is allocated and initialized to zero. It panics if r is
+// empty.
+func NewImage(f Format, r image.Rectangle) *Image {
+	if r.Empty() {
+		panic("empty image bounds")
+	}
+	return newImage(f, r)
+}
+
+// NewImageWithStride is like [NewImage] but uses stride as the number
+// of bytes between the starts of vertically adjacent pixels, such as
+// when rows need to be padded for alignment. It returns an error if
+// stride is too small to fit a row of pixels.
+func NewImageWithStride(f Format, r image.Rectangle, stride int) (*Image, error) {
+	img := Image{
+		Format: f,
+		Rect:   r,
+		Pitch:  stride,
+	}
+	if tight := img.rowBytes(f.Size()); stride < tight {
+		return nil, fmt.Errorf("stride %v is less than minimum of %v", stride, tight)
+	}
+	img.Pix = make([]byte, stride*r.Dy())
+	return &img, nil
+}
+
+func newImage(f Format, r image.Rectangle) *Image {
+	img := Image{
+		Format: f,
+		Rect:   r,
+	}
+	img.Pix = make([]byte, img.rowBytes(f.Size())*r.Dy())
+	return &img
 }
 
 func (img *Image) Bounds() image.Rectangle { return img.Rect }
@@ -59,6 +114,12 @@ func (img *Image) At(x, y int) color.Color {
 	size := img.Format.Size()
 	c := Color{Format: img.Format}
 
+	if size == 0 {
+		i, bit := img.BitOffset(x, y)
+		c.Data[0] = img.Pix[i] >> bit & 1
+		return &c
+	}
+
 	i := img.pixOffset(x, y, img.stride(size), size)
 	s := img.Pix[i : i+size : i+size]
 	copy(c.slice(size), s)
@@ -66,16 +127,70 @@ func (img *Image) At(x, y int) color.Color {
 	return &c
 }
 
+// RGBA64At returns the color of the pixel at (x, y) without
+// allocating.
+func (img *Image) RGBA64At(x, y int) color.RGBA64 {
+	if !(image.Point{x, y}.In(img.Rect)) {
+		return color.RGBA64{}
+	}
+
+	var r, g, b, a uint32
+	size := img.Format.Size()
+	if size == 0 {
+		r, g, b, a = img.At(x, y).RGBA()
+	} else {
+		i := img.pixOffset(x, y, img.stride(size), size)
+		r, g, b, a = img.Format.Read(img.Pix[i : i+size : i+size])
+	}
+	return color.RGBA64{uint16(r), uint16(g), uint16(b), uint16(a)}
+}
+
+// Stride returns the number of bytes between the starts of vertically
+// adjacent pixels. This is Pitch if it is set and the length of a
+// tightly packed row otherwise.
 func (img *Image) Stride() int {
 	return img.stride(img.Format.Size())
 }
 
 func (img *Image) stride(size int) int {
+	if img.Pitch != 0 {
+		return img.Pitch
+	}
+	return img.rowBytes(size)
+}
+
+// rowBytes returns the number of bytes of pixel data in each row,
+// excluding any padding.
+func (img *Image) rowBytes(size int) int {
+	if size == 0 {
+		return (img.Rect.Dx() + 7) / 8
+	}
 	return size * img.Rect.Dx()
 }
 
+// PixOffset returns the index of the first byte of the pixel at (x,
+// y) in Pix. For formats with a size of zero, such as [Bitmap1], it
+// instead returns the index of the pixel's bit, counting from the most
+// significant bit of the first byte of Pix. See [Image.BitOffset].
 func (img *Image) PixOffset(x, y int) int {
-	return img.pixOffset(x, y, img.Stride(), img.Format.Size())
+	size := img.Format.Size()
+	if size == 0 {
+		i, bit := img.BitOffset(x, y)
+		return i*8 + 7 - bit
+	}
+	return img.pixOffset(x, y, img.stride(size), size)
+}
+
+// BitOffset returns the index in Pix of the byte containing the pixel
+// at (x, y) and the index of the pixel's bit in that byte, where 0 is
+// the least significant bit. It is intended for use with formats with
+// a size of zero, such as [Bitmap1], which pack eight pixels into each
+// byte with the leftmost pixel in the most significant bit. Each row
+// starts on a byte boundary.
+func (img *Image) BitOffset(x, y int) (byteOffset, bitIndex int) {
+	x -= img.Rect.Min.X
+	y -= img.Rect.Min.Y
+	return (img.Stride() * y) + (x / 8), 7 - (x % 8)
 }
 
 func (img *Image) pixOffset(x, y, stride, size int) int {
@@ -90,8 +205,379 @@ func (img *Image) Set(x, y int, c color.Color) {
 	}
 
 	size := img.Format.Size()
-	i := img.pixOffset(x, y, img.stride(size), size)
 	c1 := img.ColorModel().Convert(c).(*Color)
+
+	if size == 0 {
+		i, bit := img.BitOffset(x, y)
+		img.Pix[i] = img.Pix[i]&^(1<<bit) | (c1.Data[0]&1)<<bit
+		return
+	}
+
+	i := img.pixOffset(x, y, img.stride(size), size)
 	s := img.Pix[i : i+size : i+size]
 	copy(s, c1.slice(size))
 }
+
+// Rows returns an iterator over the rows of img. Each yielded slice
+// is a view into Pix, so modifying it modifies the image. For formats
+// with a size of zero, such as [Bitmap1], each row contains the bytes
+// into which the row's pixels are packed.
+func (img *Image) Rows() iter.Seq2[int, []byte] {
+	return func(yield func(int, []byte) bool) {
+		for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+			if !yield(y, img.Row(y)) {
+				return
+			}
+		}
+	}
+}
+
+// Row returns the pixel data for the row of img at y as a view into
+// Pix, not including any padding at the end of the row. If y is not
+// within the bounds of img, it returns nil.
+func (img *Image) Row(y int) []byte {
+	if (y < img.Rect.Min.Y) || (y >= img.Rect.Max.Y) {
+		return nil
+	}
+
+	size := img.Format.Size()
+	width := img.rowBytes(size)
+	i := img.pixOffset(img.Rect.Min.X, y, img.stride(size), size)
+	return img.Pix[i : i+width : i+width]
+}
+
+// Cols returns an iterator over the columns of img. Because columns
+// are not contiguous in Pix, each yielded slice is a freshly
+// allocated copy of the column's pixels in top-to-bottom order. It
+// panics if the format of img has a size of zero, such as [Bitmap1].
+func (img *Image) Cols() iter.Seq2[int, []byte] {
+	size := img.Format.Size()
+	if size == 0 {
+		panic("Cols does not support formats with a size of zero")
+	}
+
+	return func(yield func(int, []byte) bool) {
+		stride := img.stride(size)
+		for x := img.Rect.Min.X; x < img.Rect.Max.X; x++ {
+			col := make([]byte, 0, img.Rect.Dy()*size)
+			for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+				i := img.pixOffset(x, y, stride, size)
+				col = append(col, img.Pix[i:i+size]...)
+			}
+			if !yield(x, col) {
+				return
+			}
+		}
+	}
+}
+
+// Subsample returns a new image containing every stepX-th pixel
+// horizontally and every stepY-th pixel vertically of img, starting
+// with img.Rect.Min. Because the selected pixels are not contiguous
+// in memory, the returned image has its own copy of the pixel data.
+// Its bounds start at the origin. It panics if the format of img has a
+// size of zero, such as [Bitmap1].
+func (img *Image) Subsample(stepX, stepY int) *Image {
+	if (stepX < 1) || (stepY < 1) {
+		panic("invalid subsample step")
+	}
+
+	size := img.Format.Size()
+	if size == 0 {
+		panic("Subsample does not support formats with a size of zero")
+	}
+	w := (img.Rect.Dx() + stepX - 1) / stepX
+	h := (img.Rect.Dy() + stepY - 1) / stepY
+	dst := Image{
+		Format: img.Format,
+		Rect:   image.Rect(0, 0, w, h),
+		Pix:    make([]byte, 0, w*h*size),
+	}
+	for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y += stepY {
+		for x := img.Rect.Min.X; x < img.Rect.Max.X; x += stepX {
+			i := img.PixOffset(x, y)
+			dst.Pix = append(dst.Pix, img.Pix[i:i+size]...)
+		}
+	}
+
+	return &dst
+}
+
+// ResizeTo returns a new image with the same format as img scaled to
+// w by h pixels using bilinear interpolation. The bounds of the
+// returned image start at the origin. It panics if the format of img
+// has a size of zero, such as [Bitmap1].
+func (img *Image) ResizeTo(w, h int) *Image {
+	if img.Format.Size() == 0 {
+		panic("ResizeTo does not support formats with a size of zero")
+	}
+
+	dst := newImage(img.Format, image.Rect(0, 0, w, h))
+	if img.Rect.Empty() {
+		return dst
+	}
+
+	sw, sh := img.Rect.Dx(), img.Rect.Dy()
+	read := func(x, y int) [4]float64 {
+		r, g, b, a := img.Format.Read(img.Pix[img.PixOffset(img.Rect.Min.X+x, img.Rect.Min.Y+y):])
+		return [...]float64{float64(r), float64(g), float64(b), float64(a)}
+	}
+	sample := func(v float64, max int) (i0, i1 int, t float64) {
+		v = min(float64(max-1), v)
+		if v < 0 {
+			return 0, 0, 0
+		}
+		i0 = int(v)
+		return i0, min(i0+1, max-1), v - float64(i0)
+	}
+
+	for y := range h {
+		y0, y1, ty := sample((float64(y)+0.5)*float64(sh)/float64(h)-0.5, sh)
+		for x := range w {
+			x0, x1, tx := sample((float64(x)+0.5)*float64(sw)/float64(w)-0.5, sw)
+
+			p00, p10, p01, p11 := read(x0, y0), read(x1, y0), read(x0, y1), read(x1, y1)
+			var c [4]uint32
+			for i := range c {
+				top := p00[i] + (p10[i]-p00[i])*tx
+				bottom := p01[i] + (p11[i]-p01[i])*tx
+				c[i] = uint32(top + (bottom-top)*ty + 0.5)
+			}
+			dst.Format.Write(dst.Pix[dst.PixOffset(x, y):], c[0], c[1], c[2], c[3])
+		}
+	}
+
+	return dst
+}
+
+// CountColors returns the number of unique raw pixel values in img.
+func (img *Image) CountColors() int {
+	return img.CountColorsMax(-1)
+}
+
+// CountColorsMax is like [Image.CountColors] but stops counting as
+// soon as max unique pixel values have been found. If max is
+// negative, there is no limit.
+func (img *Image) CountColorsMax(max int) int {
+	size := img.Format.Size()
+	colors := make(map[[8]byte]struct{})
+	if size == 0 {
+		for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+			for x := img.Rect.Min.X; x < img.Rect.Max.X; x++ {
+				if len(colors) == max {
+					return max
+				}
+				colors[img.At(x, y).(*Color).Data] = struct{}{}
+			}
+		}
+		return len(colors)
+	}
+
+	for _, row := range img.Rows() {
+		for i := 0; i < len(row); i += size {
+			if len(colors) == max {
+				return max
+			}
+
+			var c [8]byte
+			copy(c[:], row[i:i+size])
+			colors[c] = struct{}{}
+		}
+	}
+	return len(colors)
+}
+
+// SubImage returns an image representing the portion of img visible
+// through r. The returned image shares pixels with img and retains
+// its stride. If r does not intersect the bounds of img, nil is
+// returned. It panics if the format of img has a size of zero, such as
+// [Bitmap1].
+func (img *Image) SubImage(r image.Rectangle) *Image {
+	if img.Format.Size() == 0 {
+		panic("SubImage does not support formats with a size of zero")
+	}
+
+	r = r.Intersect(img.Rect)
+	if r.Empty() {
+		return nil
+	}
+
+	return &Image{
+		Format: img.Format,
+		Rect:   r,
+		Pix:    img.Pix[img.PixOffset(r.Min.X, r.Min.Y):],
+		Pitch:  img.Stride(),
+	}
+}
+
+// TileInto splits img into cols*rows equally sized tiles and returns
+// them in row-major order. The tiles share pixels with img. If the
+// size of img is not evenly divisible, the remaining pixels along the
+// right and bottom edges are not included in any tile. It panics if
+// cols or rows is not positive or if img is too small for every tile
+// to contain at least one pixel.
+func (img *Image) TileInto(cols, rows int) []*Image {
+	if (cols < 1) || (rows < 1) {
+		panic("invalid tile count")
+	}
+
+	size := image.Pt(img.Rect.Dx()/cols, img.Rect.Dy()/rows)
+	if (size.X == 0) || (size.Y == 0) {
+		panic("image too small for tile count")
+	}
+
+	tiles := make([]*Image, 0, cols*rows)
+	for row := range rows {
+		for col := range cols {
+			p := img.Rect.Min.Add(image.Pt(col*size.X, row*size.Y))
+			tiles = append(tiles, img.SubImage(image.Rectangle{Min: p, Max: p.Add(size)}))
+		}
+	}
+	return tiles
+}
+
+// IsZero returns true if every byte of every pixel in img is zero.
+func (img *Image) IsZero() bool {
+	zero := make([]byte, img.rowBytes(img.Format.Size()))
+	for _, row := range img.Rows() {
+		if !bytes.Equal(row, zero) {
+			return false
+		}
+	}
+	return true
+}
+
+// Rotate180 returns a copy of img rotated by 180 degrees about its
+// center. The returned image has the same bounds as img.
+func (img *Image) Rotate180() *Image {
+	size := img.Format.Size()
+	dst := newImage(img.Format, img.Rect)
+
+	if size == 0 {
+		for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+			for x := img.Rect.Min.X; x < img.Rect.Max.X; x++ {
+				si, sbit := img.BitOffset(x, y)
+				di, dbit := dst.BitOffset(img.Rect.Min.X+img.Rect.Max.X-1-x, img.Rect.Min.Y+img.Rect.Max.Y-1-y)
+				dst.Pix[di] |= (img.Pix[si] >> sbit & 1) << dbit
+			}
+		}
+		return dst
+	}
+
+	i := len(dst.Pix)
+	for _, row := range img.Rows() {
+		for x := 0; x < len(row); x += size {
+			i -= size
+			copy(dst.Pix[i:i+size], row[x:x+size])
+		}
+	}
+
+	return dst
+}
+
+// Fill sets every pixel in the intersection of r and the bounds of
+// img to c.
+func (img *Image) Fill(r image.Rectangle, c color.Color) {
+	r = r.Intersect(img.Rect)
+	size := img.Format.Size()
+	if size == 0 {
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			for x := r.Min.X; x < r.Max.X; x++ {
+				img.Set(x, y, c)
+			}
+		}
+		return
+	}
+
+	pix := img.ColorModel().Convert(c).(*Color).slice(size)
+	stride := img.stride(size)
+	width := r.Dx() * size
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		i := img.pixOffset(r.Min.X, y, stride, size)
+		row := img.Pix[i : i+width : i+width]
+
+		n := copy(row, pix)
+		for n < len(row) {
+			n += copy(row[n:], row[:n])
+		}
+	}
+}
+
+// Clear sets every byte of every pixel in img to zero, which, for
+// most formats, is transparent black.
+func (img *Image) Clear() {
+	for _, row := range img.Rows() {
+		clear(row)
+	}
+}
+
+// ForEachPixel calls f for every pixel in img in row-major order. The
+// slice passed to f is a view into Pix containing the raw data of the
+// pixel at (x, y), so it can be modified in place. It panics if the
+// format of img has a size of zero, such as [Bitmap1].
+func (img *Image) ForEachPixel(f func(x, y int, pix []byte)) {
+	size := img.Format.Size()
+	if size == 0 {
+		panic("ForEachPixel does not support formats with a size of zero")
+	}
+	for y, row := range img.Rows() {
+		x := img.Rect.Min.X
+		for i := 0; i < len(row); i += size {
+			f(x, y, row[i:i+size:i+size])
+			x++
+		}
+	}
+}
+
+// MapPixels returns a new image with the same format and bounds as
+// img where each pixel is determined by calling f with the raw data
+// of the corresponding pixel in img. f returns alpha-premultiplied
+// RGBA values.
+func (img *Image) MapPixels(f func(x, y int, src []byte) (r, g, b, a uint32)) *Image {
+	dst := newImage(img.Format, img.Rect)
+	img.ForEachPixel(func(x, y int, pix []byte) {
+		r, g, b, a := f(x, y, pix)
+		dst.Format.Write(dst.Pix[dst.PixOffset(x, y):], r, g, b, a)
+	})
+	return dst
+}
+
+// WriteTo implements io.WriterTo by writing the pixel data of each row
+// of img to w in order. Padding at the ends of rows is not written.
+func (img *Image) WriteTo(w io.Writer) (int64, error) {
+	size := img.Format.Size()
+	if img.stride(size) == img.rowBytes(size) {
+		start := img.pixOffset(img.Rect.Min.X, img.Rect.Min.Y, img.stride(size), size)
+		n, err := w.Write(img.Pix[start : start+img.rowBytes(size)*img.Rect.Dy()])
+		return int64(n), err
+	}
+
+	var total int64
+	for _, row := range img.Rows() {
+		n, err := w.Write(row)
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// ReadFrom implements io.ReaderFrom by reading the pixel data of each
+// row of img from r in order, the inverse of [Image.WriteTo]. If r
+// does not contain enough data to fill every row, an error is
+// returned.
+func (img *Image) ReadFrom(r io.Reader) (int64, error) {
+	var total int64
+	for _, row := range img.Rows() {
+		n, err := io.ReadFull(r, row)
+		total += int64(n)
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return total, err
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,69 @@
+package format
+
+import "image"
+
+// SaturatingAdd adds the channels of each pixel in src to the
+// corresponding pixel in dst, clamping each channel to 0xFFFF instead
+// of overflowing. src.Rect.Min is aligned with dstPt in dst. Pixels
+// that fall outside of dst are ignored.
+//
+// Because the values are alpha-premultiplied, color channels are
+// additionally clamped to the resulting alpha. It panics if the format
+// of either image has a size of zero, such as [Bitmap1].
+func SaturatingAdd(dst, src *Image, dstPt image.Point) {
+	if (dst.Format.Size() == 0) || (src.Format.Size() == 0) {
+		panic("SaturatingAdd does not support formats with a size of zero")
+	}
+
+	off := dstPt.Sub(src.Rect.Min)
+	r := src.Rect.Add(off).Intersect(dst.Rect)
+
+	var buf [8]byte
+	dsize := dst.Format.Size()
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			sr, sg, sb, sa := src.Format.Read(src.Pix[src.PixOffset(x-off.X, y-off.Y):])
+
+			i := dst.PixOffset(x, y)
+			p := dst.Pix[i : i+dsize : i+dsize]
+			dr, dg, db, da := dst.Format.Read(p)
+
+			a := min(da+sa, 0xFFFF)
+			dst.Format.Write(
+				buf[:dsize],
+				min(dr+sr, a),
+				min(dg+sg, a),
+				min(db+sb, a),
+				a,
+			)
+			copy(p, buf[:dsize])
+		}
+	}
+}
+
+// ConvertImage converts the pixels of src to the format of dst and
+// stores them in dst. Unlike using At and Set, it does not allocate.
+// It panics if dst and src do not have the same bounds or if the
+// format of either has a size of zero, such as [Bitmap1].
+func ConvertImage(dst, src *Image) {
+	if dst.Rect != src.Rect {
+		panic("mismatched image bounds")
+	}
+
+	ssize, dsize := src.Format.Size(), dst.Format.Size()
+	if (ssize == 0) || (dsize == 0) {
+		panic("ConvertImage does not support formats with a size of zero")
+	}
+
+	sstride, dstride := src.stride(ssize), dst.stride(dsize)
+	for y := src.Rect.Min.Y; y < src.Rect.Max.Y; y++ {
+		si := src.pixOffset(src.Rect.Min.X, y, sstride, ssize)
+		di := dst.pixOffset(dst.Rect.Min.X, y, dstride, dsize)
+		for range src.Rect.Dx() {
+			r, g, b, a := src.Format.Read(src.Pix[si : si+ssize])
+			dst.Format.Write(dst.Pix[di:di+dsize], r, g, b, a)
+			si += ssize
+			di += dsize
+		}
+	}
+}
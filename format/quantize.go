@@ -0,0 +1,137 @@
+package format
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"slices"
+)
+
+// Quantize reduces the colors in img to a palette of at most n colors
+// using the median cut algorithm and returns the result as a paletted
+// image. n must be between 1 and 256, inclusive. Formats with a size
+// of zero, such as [Bitmap1], are not supported.
+func (img *Image) Quantize(n int) (*image.Paletted, error) {
+	if (n < 1) || (n > 256) {
+		return nil, errors.New("palette size must be between 1 and 256")
+	}
+	if img.Format.Size() == 0 {
+		return nil, errors.New("formats with a size of zero are not supported")
+	}
+
+	pixels := make([]color.RGBA64, 0, img.Rect.Dx()*img.Rect.Dy())
+	for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+		for x := img.Rect.Min.X; x < img.Rect.Max.X; x++ {
+			r, g, b, a := img.Format.Read(img.Pix[img.PixOffset(x, y):])
+			pixels = append(pixels, color.RGBA64{uint16(r), uint16(g), uint16(b), uint16(a)})
+		}
+	}
+
+	// Splitting boxes sorts their pixels in place, so they need their
+	// own copy to keep pixels in raster order for indexing below.
+	boxes := []colorBox{newColorBox(slices.Clone(pixels))}
+	for len(boxes) < n {
+		i := slices.IndexFunc(boxes, func(b colorBox) bool { return b.width > 0 })
+		for j := i + 1; (i >= 0) && (j < len(boxes)); j++ {
+			if boxes[j].width > boxes[i].width {
+				i = j
+			}
+		}
+		if i < 0 {
+			break
+		}
+
+		lo, hi := boxes[i].split()
+		boxes[i] = lo
+		boxes = append(boxes, hi)
+	}
+
+	palette := make(color.Palette, 0, len(boxes))
+	for _, b := range boxes {
+		if len(b.pixels) > 0 {
+			palette = append(palette, b.average())
+		}
+	}
+
+	dst := image.NewPaletted(img.Rect, palette)
+	var i int
+	for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+		for x := img.Rect.Min.X; x < img.Rect.Max.X; x++ {
+			dst.SetColorIndex(x, y, uint8(palette.Index(pixels[i])))
+			i++
+		}
+	}
+
+	return dst, nil
+}
+
+// colorBox is a set of colors used during median cut quantization.
+type colorBox struct {
+	pixels  []color.RGBA64
+	channel int
+	width   uint16
+}
+
+func newColorBox(pixels []color.RGBA64) colorBox {
+	b := colorBox{pixels: pixels}
+	if len(pixels) == 0 {
+		return b
+	}
+
+	lo, hi := channels(pixels[0]), channels(pixels[0])
+	for _, p := range pixels[1:] {
+		c := channels(p)
+		for i := range c {
+			lo[i], hi[i] = min(lo[i], c[i]), max(hi[i], c[i])
+		}
+	}
+	for i := range lo {
+		if w := hi[i] - lo[i]; w > b.width {
+			b.channel, b.width = i, w
+		}
+	}
+	return b
+}
+
+// split splits b at the median of its widest channel.
+func (b colorBox) split() (lo, hi colorBox) {
+	slices.SortFunc(b.pixels, func(p1, p2 color.RGBA64) int {
+		return int(channels(p1)[b.channel]) - int(channels(p2)[b.channel])
+	})
+
+	// Make sure that identical values don't end up on both sides of
+	// the split so that each box is guaranteed to shrink.
+	mid := len(b.pixels) / 2
+	v := channels(b.pixels[mid])[b.channel]
+	if channels(b.pixels[0])[b.channel] == v {
+		for (mid < len(b.pixels)) && (channels(b.pixels[mid])[b.channel] == v) {
+			mid++
+		}
+	} else {
+		for channels(b.pixels[mid-1])[b.channel] == v {
+			mid--
+		}
+	}
+
+	return newColorBox(b.pixels[:mid]), newColorBox(b.pixels[mid:])
+}
+
+func (b colorBox) average() color.RGBA64 {
+	var sum [4]uint64
+	for _, p := range b.pixels {
+		for i, c := range channels(p) {
+			sum[i] += uint64(c)
+		}
+	}
+	n := uint64(len(b.pixels))
+	return color.RGBA64{
+		R: uint16(sum[0] / n),
+		G: uint16(sum[1] / n),
+		B: uint16(sum[2] / n),
+		A: uint16(sum[3] / n),
+	}
+}
+
+func channels(c color.RGBA64) [4]uint16 {
+	return [...]uint16{c.R, c.G, c.B, c.A}
+}
@@ -0,0 +1,88 @@
+package format
+
+import "image"
+
+// FromStdImage returns a copy of img converted to the format f. The
+// common image types from the standard library are converted directly
+// from their pixel data.
+func FromStdImage(img image.Image, f Format) *Image {
+	bounds := img.Bounds()
+	dst := newImage(f, bounds)
+	if f.Size() == 0 {
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				dst.Set(x, y, img.At(x, y))
+			}
+		}
+		return dst
+	}
+
+	switch img := img.(type) {
+	case *image.RGBA:
+		dst.ForEachPixel(func(x, y int, pix []byte) {
+			s := img.Pix[img.PixOffset(x, y):]
+			f.Write(pix, uint32(s[0])*0x101, uint32(s[1])*0x101, uint32(s[2])*0x101, uint32(s[3])*0x101)
+		})
+
+	case *image.NRGBA:
+		dst.ForEachPixel(func(x, y int, pix []byte) {
+			s := img.Pix[img.PixOffset(x, y):]
+			a := uint32(s[3]) * 0x101
+			f.Write(pix, uint32(s[0])*a/0xFF, uint32(s[1])*a/0xFF, uint32(s[2])*a/0xFF, a)
+		})
+
+	case image.RGBA64Image:
+		dst.ForEachPixel(func(x, y int, pix []byte) {
+			c := img.RGBA64At(x, y)
+			f.Write(pix, uint32(c.R), uint32(c.G), uint32(c.B), uint32(c.A))
+		})
+
+	default:
+		dst.ForEachPixel(func(x, y int, pix []byte) {
+			r, g, b, a := img.At(x, y).RGBA()
+			f.Write(pix, r, g, b, a)
+		})
+	}
+
+	return dst
+}
+
+// AsARGB8888 reinterprets img as an ARGB8888 image without allocating
+// a new pixel buffer. Because ARGB8888 stores its color components in
+// a different order, the pixel data of img is modified in place to
+// swap them, so img should not be used after calling this function.
+func AsARGB8888(img *image.NRGBA) *Image {
+	for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+		i := img.PixOffset(img.Rect.Min.X, y)
+		row := img.Pix[i : i+4*img.Rect.Dx()]
+		for j := 0; j < len(row); j += 4 {
+			row[j], row[j+2] = row[j+2], row[j]
+		}
+	}
+
+	return &Image{
+		Format: ARGB8888,
+		Rect:   img.Rect,
+		Pix:    img.Pix,
+		Pitch:  img.Stride,
+	}
+}
+
+// ToStdImage converts img to an *image.RGBA. The pixel data is always
+// copied, so the returned image never shares memory with img. None of
+// the predefined formats match the alpha-premultiplied layout of
+// image.RGBA.
+func ToStdImage(img *Image) *image.RGBA {
+	dst := image.NewRGBA(img.Rect)
+	for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+		for x := img.Rect.Min.X; x < img.Rect.Max.X; x++ {
+			c := img.RGBA64At(x, y)
+			i := dst.PixOffset(x, y)
+			dst.Pix[i+0] = uint8(c.R >> 8)
+			dst.Pix[i+1] = uint8(c.G >> 8)
+			dst.Pix[i+2] = uint8(c.B >> 8)
+			dst.Pix[i+3] = uint8(c.A >> 8)
+		}
+	}
+	return dst
+}
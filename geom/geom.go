@@ -2,6 +2,12 @@
 //
 // It is patterned heavily after image.Rectangle and image.Point, but
 // vastly extends their capabilities.
+//
+// The layout functions, such as [TileEvenVertically], do not treat
+// empty rectangles specially. If the rectangle being tiled is empty,
+// as reported by [Rect.Empty], every tile produced is also empty,
+// though a tile may still extend along whichever axis of the original
+// rectangle was non-zero.
 package geom
 
 import "golang.org/x/exp/constraints"
@@ -21,4 +27,60 @@ const (
 	EdgeBottom
 	EdgeLeft
 	EdgeRight
+
+	EdgeTopLeft     = EdgeTop | EdgeLeft
+	EdgeTopRight    = EdgeTop | EdgeRight
+	EdgeBottomLeft  = EdgeBottom | EdgeLeft
+	EdgeBottomRight = EdgeBottom | EdgeRight
+
+	EdgeAll = EdgeTop | EdgeBottom | EdgeLeft | EdgeRight
 )
+
+// Has returns true if e contains all of the edges in other.
+func (e Edges) Has(other Edges) bool {
+	return e&other == other
+}
+
+// Add returns e with all of the edges in other added to it.
+func (e Edges) Add(other Edges) Edges {
+	return e | other
+}
+
+// Remove returns e with all of the edges in other removed from it.
+func (e Edges) Remove(other Edges) Edges {
+	return e &^ other
+}
+
+// Anchor is one of nine points on a rectangle that another rectangle
+// can be aligned to. See [Rect.AlignTo].
+type Anchor int
+
+const (
+	AnchorNW Anchor = iota
+	AnchorN
+	AnchorNE
+	AnchorW
+	AnchorCenter
+	AnchorE
+	AnchorSW
+	AnchorS
+	AnchorSE
+)
+
+var anchorEdges = [...]Edges{
+	AnchorNW:     EdgeTopLeft,
+	AnchorN:      EdgeTop,
+	AnchorNE:     EdgeTopRight,
+	AnchorW:      EdgeLeft,
+	AnchorCenter: EdgeNone,
+	AnchorE:      EdgeRight,
+	AnchorSW:     EdgeBottomLeft,
+	AnchorS:      EdgeBottom,
+	AnchorSE:     EdgeBottomRight,
+}
+
+// Edges returns the edges that correspond to the anchor for use with
+// [Align]. AnchorCenter has no edges.
+func (a Anchor) Edges() Edges {
+	return anchorEdges[a]
+}
@@ -0,0 +1,75 @@
+package geom_test
+
+import (
+	"testing"
+
+	"deedles.dev/ximage/geom"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEdgesHas(t *testing.T) {
+	require.True(t, geom.EdgeAll.Has(geom.EdgeTop))
+	require.True(t, geom.EdgeAll.Has(geom.EdgeTop|geom.EdgeLeft))
+	require.False(t, geom.EdgeNone.Has(geom.EdgeTop))
+	require.False(t, geom.EdgeTop.Has(geom.EdgeTop|geom.EdgeLeft))
+	require.True(t, geom.EdgeTopLeft.Has(geom.EdgeLeft))
+	require.False(t, geom.EdgeBottomRight.Has(geom.EdgeTop))
+}
+
+func TestEdgesAddRemove(t *testing.T) {
+	e := geom.EdgeNone.Add(geom.EdgeTop).Add(geom.EdgeLeft | geom.EdgeRight)
+	require.Equal(t, geom.EdgeTop|geom.EdgeLeft|geom.EdgeRight, e)
+	require.Equal(t, e, e.Add(geom.EdgeTop))
+	require.Equal(t, geom.EdgeAll, e.Add(geom.EdgeBottom))
+
+	require.Equal(t, geom.EdgeTop|geom.EdgeRight, e.Remove(geom.EdgeLeft))
+	require.Equal(t, geom.EdgeTop, e.Remove(geom.EdgeLeft|geom.EdgeRight|geom.EdgeBottom))
+	require.Equal(t, geom.EdgeNone, geom.EdgeAll.Remove(geom.EdgeAll))
+}
+
+func TestAlign(t *testing.T) {
+	outer := geom.Rt(0, 0, 100, 100)
+	inner := geom.Rt(0, 0, 20, 10)
+
+	tests := []struct {
+		edges geom.Edges
+		out   geom.Rect[int]
+	}{
+		{geom.EdgeNone, geom.Rt(40, 45, 60, 55)},
+		{geom.EdgeTop, geom.Rt(40, 0, 60, 10)},
+		{geom.EdgeTopLeft, geom.Rt(0, 0, 20, 10)},
+		{geom.EdgeTopRight, geom.Rt(80, 0, 100, 10)},
+		{geom.EdgeBottomLeft, geom.Rt(0, 90, 20, 100)},
+		{geom.EdgeBottomRight, geom.Rt(80, 90, 100, 100)},
+		{geom.EdgeLeft | geom.EdgeRight, geom.Rt(0, 45, 100, 55)},
+		{geom.EdgeAll, outer},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.out, geom.Align(outer, inner, test.edges), "%b", test.edges)
+	}
+}
+
+func TestAlignTo(t *testing.T) {
+	outer := geom.Rt(0, 0, 100, 100)
+	r := geom.Rt(300, 300, 320, 310)
+
+	tests := []struct {
+		anchor geom.Anchor
+		out    geom.Rect[int]
+	}{
+		{geom.AnchorNW, geom.Rt(0, 0, 20, 10)},
+		{geom.AnchorN, geom.Rt(40, 0, 60, 10)},
+		{geom.AnchorNE, geom.Rt(80, 0, 100, 10)},
+		{geom.AnchorW, geom.Rt(0, 45, 20, 55)},
+		{geom.AnchorCenter, geom.Rt(40, 45, 60, 55)},
+		{geom.AnchorE, geom.Rt(80, 45, 100, 55)},
+		{geom.AnchorSW, geom.Rt(0, 90, 20, 100)},
+		{geom.AnchorS, geom.Rt(40, 90, 60, 100)},
+		{geom.AnchorSE, geom.Rt(80, 90, 100, 100)},
+	}
+	for _, test := range tests {
+		out := r.AlignTo(outer, test.anchor)
+		require.Equal(t, test.out, out, "anchor %v", test.anchor)
+		require.Equal(t, r.Size(), out.Size(), "anchor %v", test.anchor)
+	}
+}
@@ -1,7 +1,11 @@
 package geom
 
 import (
+	"fmt"
 	"iter"
+	"math"
+	"math/bits"
+	"slices"
 
 	"deedles.dev/xiter"
 )
@@ -48,20 +52,73 @@ func TileRightThenDown[T Scalar](tiles []Rect[T], r Rect[T]) {
 	insertTilesFromSeq(tiles, TiledRightThenDown(len(tiles), r))
 }
 
+// TileRightThenDownDepth is like [TileRightThenDown] but limits the
+// depth of the recursive splitting to prevent tiles from becoming too
+// small. No tile is the result of more than maxDepth halvings of r,
+// so at most 2^maxDepth tiles are produced. When there are more tiles
+// than [TileRightThenDown] could place within that depth, the earlier
+// sections are split further instead of the last one, so that
+// requesting 2^maxDepth or more tiles results in an even grid. A
+// maxDepth of zero or less produces a single tile that covers all of
+// r. Any remaining elements of tiles are set to the zero Rect. The
+// number of tiles that were filled is returned.
+func TileRightThenDownDepth[T Scalar](tiles []Rect[T], r Rect[T], maxDepth int) int {
+	maxDepth = max(maxDepth, 0)
+	n := len(tiles)
+	if maxDepth < bits.UintSize-1 {
+		n = min(n, 1<<maxDepth)
+	}
+	if n == 0 {
+		return 0
+	}
+
+	tileDepth(tiles[:n], r, maxDepth, hsplitHalf[T], vsplitHalf[T])
+	clear(tiles[n:])
+	return n
+}
+
+// tileDepth fills tiles by splitting r, with no tile being the result
+// of more than depth halvings. len(tiles) must be no more than
+// 2^depth.
+func tileDepth[T Scalar](tiles []Rect[T], r Rect[T], depth int, split, next func(Rect[T]) (Rect[T], Rect[T])) {
+	for len(tiles) > 1 {
+		// Give the first half as few tiles as possible while still
+		// leaving room for the rest in the second half.
+		first := 1
+		if depth-1 < bits.UintSize-1 {
+			first = max(1, len(tiles)-1<<(depth-1))
+		}
+
+		var c Rect[T]
+		c, r = split(r)
+		tileDepth(tiles[:first], c, depth-1, next, split)
+
+		tiles, depth = tiles[first:], depth-1
+		split, next = next, split
+	}
+
+	tiles[0] = r
+}
+
 // TiledRightThenDown is the same as [TileRightThenDown] but yields
 // the successive tiles from an interator instead of inserting them
 // into a slice.
 func TiledRightThenDown[T Scalar](numtiles int, r Rect[T]) iter.Seq[Rect[T]] {
 	return func(yield func(Rect[T]) bool) {
+		if numtiles <= 0 {
+			return
+		}
+
 		split, next := hsplitHalf[T], vsplitHalf[T]
 
-		c, n := split(r)
+		n := r
 		for range numtiles - 1 {
+			var c Rect[T]
+			c, n = split(n)
 			if !yield(c) {
 				return
 			}
 
-			c, n = split(n)
 			split, next = next, split
 		}
 
@@ -72,7 +129,8 @@ func TiledRightThenDown[T Scalar](numtiles int, r Rect[T]) iter.Seq[Rect[T]] {
 // TileTwoThirdsSidebar arranges and resizes the elements of tiles so
 // that the result are a series of rectangles where the first is
 // two-thirds the width of r and the rest are arranged vertically in
-// an even split in the remaining space.
+// an even split in the remaining space. A single tile is given all of
+// r.
 func TileTwoThirdsSidebar[T Scalar](tiles []Rect[T], r Rect[T]) {
 	insertTilesFromSeq(tiles, TiledTwoThirdsSidebar(len(tiles), r))
 }
@@ -82,6 +140,14 @@ func TileTwoThirdsSidebar[T Scalar](tiles []Rect[T], r Rect[T]) {
 // of inserting them into a slice.
 func TiledTwoThirdsSidebar[T Scalar](numtiles int, r Rect[T]) iter.Seq[Rect[T]] {
 	return func(yield func(Rect[T]) bool) {
+		if numtiles <= 0 {
+			return
+		}
+		if numtiles == 1 {
+			yield(r)
+			return
+		}
+
 		first, rem := hsplit(r, 2*r.Dx()/3)
 		if !yield(first) {
 			return
@@ -119,6 +185,10 @@ func TileEvenVertically[T Scalar](tiles []Rect[T], r Rect[T]) {
 // it yields the tiles from an iterator.
 func TiledEvenVertically[T Scalar](numtiles int, r Rect[T]) iter.Seq[Rect[T]] {
 	return func(yield func(Rect[T]) bool) {
+		if numtiles <= 0 {
+			return
+		}
+
 		size := Pt(0, r.Dy()/T(numtiles))
 		c, _ := vsplit(r, size.Y)
 		for range numtiles {
@@ -139,15 +209,21 @@ func TiledEvenVertically[T Scalar](numtiles int, r Rect[T]) iter.Seq[Rect[T]] {
 //
 // will produce
 //
-// ----------
-// |  |  |  |
-// ----------
+//	----------
+//	|  |  |  |
+//	----------
 func TileEvenHorizontally[T Scalar](tiles []Rect[T], r Rect[T]) {
 	insertTilesFromSeq(tiles, TiledEvenHorizontally(len(tiles), r))
 }
 
+// TiledEvenHorizontally is the same as [TileEvenHorizontally] except
+// that it yields the tiles from an iterator.
 func TiledEvenHorizontally[T Scalar](numtiles int, r Rect[T]) iter.Seq[Rect[T]] {
 	return func(yield func(Rect[T]) bool) {
+		if numtiles <= 0 {
+			return
+		}
+
 		size := Pt(r.Dx()/T(numtiles), 0)
 		c, _ := hsplit(r, size.X)
 		for range numtiles {
@@ -159,6 +235,44 @@ func TiledEvenHorizontally[T Scalar](numtiles int, r Rect[T]) iter.Seq[Rect[T]]
 	}
 }
 
+// TileQuadrants splits r in half both horizontally and vertically
+// and returns the four resulting quadrants in the order top-left,
+// top-right, bottom-left, bottom-right.
+func TileQuadrants[T Scalar](r Rect[T]) [4]Rect[T] {
+	top, bottom := vsplitHalf(r)
+	tl, tr := hsplitHalf(top)
+	bl, br := hsplitHalf(bottom)
+	return [...]Rect[T]{tl, tr, bl, br}
+}
+
+// Quadrant returns the quadrant of r with the index q in the order
+// used by [TileQuadrants]. It panics if q is not in the range [0, 3].
+func (r Rect[T]) Quadrant(q int) Rect[T] {
+	return TileQuadrants(r)[q]
+}
+
+// RowIter returns an iterator over n rows of even height that
+// together make up r, yielded alongside their indices.
+func (r Rect[T]) RowIter(n int) iter.Seq2[int, Rect[T]] {
+	return xiter.Enumerate(TiledEvenVertically(n, r))
+}
+
+// ColIter returns an iterator over n columns of even width that
+// together make up r, yielded alongside their indices.
+func (r Rect[T]) ColIter(n int) iter.Seq2[int, Rect[T]] {
+	return xiter.Enumerate(TiledEvenHorizontally(n, r))
+}
+
+// SplitN splits r evenly into n rectangles along its longer axis. If
+// r is wider than it is tall, or if it is square, it is split into
+// columns. Otherwise, it is split into rows.
+func (r Rect[T]) SplitN(n int) []Rect[T] {
+	if r.Dx() >= r.Dy() {
+		return slices.Collect(TiledEvenHorizontally(n, r))
+	}
+	return slices.Collect(TiledEvenVertically(n, r))
+}
+
 // TileRows arranges and resizes the elements of tiles to produce a
 // series of rows and columns the union of which reproduces r. The
 // final row of the table is split evenly into at most cols columns.
@@ -193,6 +307,72 @@ func TiledRows[T Scalar](numtiles int, r Rect[T], cols int) iter.Seq[Rect[T]] {
 	}
 }
 
+// TileColumns arranges and resizes the elements of tiles to produce
+// cols columns of even width, the union of which reproduces r. The
+// tiles are distributed as evenly as possible between the columns,
+// filling each column from top to bottom before moving to the next.
+// When the tiles can not be split evenly, the earlier columns each
+// contain one more tile than the later ones. If there are fewer tiles
+// than cols, only one column per tile is produced. A cols of zero or
+// less is treated as 1.
+func TileColumns[T Scalar](tiles []Rect[T], r Rect[T], cols int) {
+	insertTilesFromSeq(tiles, TiledColumns(len(tiles), r, cols))
+}
+
+// TiledColumns is the same as [TileColumns] except that it yields the
+// tiles from an iterator.
+func TiledColumns[T Scalar](numtiles int, r Rect[T], cols int) iter.Seq[Rect[T]] {
+	return func(yield func(Rect[T]) bool) {
+		if numtiles <= 0 {
+			return
+		}
+
+		cols = min(max(cols, 1), numtiles)
+		perCol, extra := numtiles/cols, numtiles%cols
+
+		for i, col := range r.ColIter(cols) {
+			numrows := perCol
+			if i < extra {
+				numrows++
+			}
+
+			for t := range TiledEvenVertically(numrows, col) {
+				if !yield(t) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// TileRowsVariable arranges and resizes the elements of tiles to
+// produce len(colsPerRow) rows of even height, the union of which
+// reproduces r. Row i is split evenly into colsPerRow[i] columns.
+func TileRowsVariable[T Scalar](tiles []Rect[T], r Rect[T], colsPerRow []int) {
+	insertTilesFromSeq(tiles, TiledRowsVariable(len(tiles), r, colsPerRow))
+}
+
+// TiledRowsVariable is the same as [TileRowsVariable] except that it
+// yields the tiles from an iterator.
+func TiledRowsVariable[T Scalar](numtiles int, r Rect[T], colsPerRow []int) iter.Seq[Rect[T]] {
+	return func(yield func(Rect[T]) bool) {
+		rows := TiledEvenVertically(len(colsPerRow), r)
+		for i, row := range xiter.Enumerate(rows) {
+			if numtiles <= 0 {
+				break
+			}
+
+			numcols := min(numtiles, colsPerRow[i])
+			for t := range TiledEvenHorizontally(numcols, row) {
+				if !yield(t) {
+					return
+				}
+			}
+			numtiles -= numcols
+		}
+	}
+}
+
 // VerticalStack returns an iterator that yields the rectangle
 // provided and then identical copies shifted downwards by its height
 // repeatedly, thus producing an infinite vertical stack of rectangles
@@ -243,27 +423,74 @@ func ArrangeVerticalStack[T Scalar](rects []Rect[T]) {
 func Align[T Scalar](outer, inner Rect[T], edges Edges) Rect[T] {
 	inner = inner.CenterAt(outer.Center())
 	switch {
-	case edges&EdgeTop != 0:
+	case edges.Has(EdgeTop):
 		inner.Min.Y, inner.Max.Y = outer.Min.Y, outer.Min.Y+inner.Dy()
-		if edges&EdgeBottom != 0 {
+		if edges.Has(EdgeBottom) {
 			inner.Max.Y = outer.Max.Y
 		}
-	case edges&EdgeBottom != 0:
+	case edges.Has(EdgeBottom):
 		inner.Min.Y, inner.Max.Y = outer.Max.Y-inner.Dy(), outer.Max.Y
 	}
 	switch {
-	case edges&EdgeLeft != 0:
+	case edges.Has(EdgeLeft):
 		inner.Min.X, inner.Max.X = outer.Min.X, outer.Min.X+inner.Dx()
-		if edges&EdgeRight != 0 {
+		if edges.Has(EdgeRight) {
 			inner.Max.X = outer.Max.X
 		}
-	case edges&EdgeRight != 0:
+	case edges.Has(EdgeRight):
 		inner.Min.X, inner.Max.X = outer.Max.X-inner.Dx(), outer.Max.X
 	}
 
 	return inner
 }
 
+// AlignTo returns r moved so that it is positioned at anchor within
+// outer without changing its size. For example, AnchorN centers r
+// horizontally along the top edge of outer while AnchorSE places it
+// in the bottom-right corner.
+func (r Rect[T]) AlignTo(outer Rect[T], anchor Anchor) Rect[T] {
+	return Align(outer, r, anchor.Edges())
+}
+
+// Tile arranges and resizes the elements of tiles to fill r using the
+// named tiling strategy. The supported strategies are
+//
+//   - "right-then-down": [TileRightThenDown]
+//   - "two-thirds-sidebar": [TileTwoThirdsSidebar]
+//   - "even-vertically": [TileEvenVertically]
+//   - "even-horizontally": [TileEvenHorizontally]
+//   - "rows": [TileRows] with as many columns as necessary to produce
+//     a roughly square grid
+//
+// If strategy is not one of the above, an error is returned and tiles
+// is left unmodified.
+func (r Rect[T]) Tile(strategy string, tiles []Rect[T]) error {
+	var tile func([]Rect[T], Rect[T])
+	switch strategy {
+	case "right-then-down":
+		tile = TileRightThenDown[T]
+	case "two-thirds-sidebar":
+		tile = TileTwoThirdsSidebar[T]
+	case "even-vertically":
+		tile = TileEvenVertically[T]
+	case "even-horizontally":
+		tile = TileEvenHorizontally[T]
+	case "rows":
+		tile = func(tiles []Rect[T], r Rect[T]) {
+			cols := int(math.Ceil(math.Sqrt(float64(len(tiles)))))
+			TileRows(tiles, r, cols)
+		}
+	default:
+		return fmt.Errorf("unknown tiling strategy %q", strategy)
+	}
+
+	if len(tiles) == 0 {
+		return nil
+	}
+	tile(tiles, r)
+	return nil
+}
+
 func insertTilesFromSeq[T Scalar](tiles []Rect[T], s iter.Seq[Rect[T]]) {
 	for i, t := range xiter.Enumerate(s) {
 		tiles[i] = t
@@ -0,0 +1,285 @@
+package geom_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"deedles.dev/ximage/geom"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTile(t *testing.T) {
+	r := geom.Rt(0, 0, 120.0, 90.0)
+	tests := map[string]func([]geom.Rect[float64], geom.Rect[float64]){
+		"right-then-down":    geom.TileRightThenDown[float64],
+		"two-thirds-sidebar": geom.TileTwoThirdsSidebar[float64],
+		"even-vertically":    geom.TileEvenVertically[float64],
+		"even-horizontally":  geom.TileEvenHorizontally[float64],
+		"rows": func(tiles []geom.Rect[float64], r geom.Rect[float64]) {
+			geom.TileRows(tiles, r, 3)
+		},
+	}
+	for name, tile := range tests {
+		t.Run(name, func(t *testing.T) {
+			expected := make([]geom.Rect[float64], 5)
+			tile(expected, r)
+
+			tiles := make([]geom.Rect[float64], 5)
+			require.Nil(t, r.Tile(name, tiles))
+			require.Equal(t, expected, tiles)
+		})
+	}
+
+	require.NotNil(t, r.Tile("spiral", make([]geom.Rect[float64], 5)))
+	require.NotNil(t, r.Tile("spiral", nil))
+
+	ri := geom.Rt(0, 0, 120, 90)
+	for name := range tests {
+		t.Run(name+"/int", func(t *testing.T) {
+			require.Nil(t, ri.Tile(name, nil))
+			require.Nil(t, ri.Tile(name, []geom.Rect[int]{}))
+
+			tiles := make([]geom.Rect[int], 1)
+			require.Nil(t, ri.Tile(name, tiles))
+			require.Equal(t, ri, tiles[0])
+
+			tiles = make([]geom.Rect[int], 5)
+			require.Nil(t, ri.Tile(name, tiles))
+			for _, tile := range tiles {
+				require.True(t, tile.In(ri), tile)
+				require.False(t, tile.Empty(), tile)
+			}
+		})
+	}
+}
+
+func BenchmarkTileRightThenDown(b *testing.B) {
+	r := geom.Rt(0, 0, 1920.0, 1080.0)
+	for _, n := range []int{4, 8, 16, 32} {
+		b.Run(fmt.Sprintf("slice/%v", n), func(b *testing.B) {
+			b.ReportAllocs()
+			tiles := make([]geom.Rect[float64], n)
+			for range b.N {
+				geom.TileRightThenDown(tiles, r)
+			}
+		})
+
+		b.Run(fmt.Sprintf("iter/%v", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for range b.N {
+				for range geom.TiledRightThenDown(n, r) {
+				}
+			}
+		})
+	}
+}
+
+func TestTileRightThenDownDepth(t *testing.T) {
+	r := geom.Rt(0, 0, 64, 64)
+
+	tiles := make([]geom.Rect[int], 6)
+	require.Equal(t, 4, geom.TileRightThenDownDepth(tiles, r, 2))
+	require.Equal(t, []geom.Rect[int]{
+		geom.Rt(0, 0, 32, 32),
+		geom.Rt(0, 32, 32, 64),
+		geom.Rt(32, 0, 64, 32),
+		geom.Rt(32, 32, 64, 64),
+	}, tiles[:4])
+	require.Equal(t, make([]geom.Rect[int], 2), tiles[4:])
+
+	expected := make([]geom.Rect[int], 3)
+	geom.TileRightThenDown(expected, r)
+	require.Equal(t, 3, geom.TileRightThenDownDepth(tiles[:3], r, 2))
+	require.Equal(t, expected, tiles[:3])
+
+	require.Equal(t, 1, geom.TileRightThenDownDepth(tiles, r, 0))
+	require.Equal(t, r, tiles[0])
+
+	r = geom.Rt(0, 0, 1024, 1024)
+	tiles = make([]geom.Rect[int], 64)
+	for n := range len(tiles) + 1 {
+		got := geom.TileRightThenDownDepth(tiles[:n], r, 4)
+		require.Equal(t, min(n, 16), got)
+
+		var area int
+		for _, tile := range tiles[:got] {
+			require.GreaterOrEqual(t, tile.Dx(), 256, tile)
+			require.GreaterOrEqual(t, tile.Dy(), 256, tile)
+			area += tile.Dx() * tile.Dy()
+		}
+		if got > 0 {
+			require.Equal(t, r.Dx()*r.Dy(), area)
+		}
+	}
+
+	for _, depth := range []int{62, 63, 64, 1000} {
+		require.Equal(t, len(tiles), geom.TileRightThenDownDepth(tiles, r, depth))
+	}
+	expected = make([]geom.Rect[int], len(tiles))
+	geom.TileRightThenDown(expected, r)
+	require.Equal(t, expected, tiles)
+}
+
+func TestTileRowsVariable(t *testing.T) {
+	r := geom.Rt(0, 0, 60, 30)
+	tiles := make([]geom.Rect[int], 6)
+	geom.TileRowsVariable(tiles, r, []int{3, 2, 1})
+	require.Equal(t, []geom.Rect[int]{
+		geom.Rt(0, 0, 20, 10),
+		geom.Rt(20, 0, 40, 10),
+		geom.Rt(40, 0, 60, 10),
+		geom.Rt(0, 10, 30, 20),
+		geom.Rt(30, 10, 60, 20),
+		geom.Rt(0, 20, 60, 30),
+	}, tiles)
+}
+
+func TestTiledEven(t *testing.T) {
+	r := geom.Rt(0, 0, 90, 60)
+
+	tiles := make([]geom.Rect[int], 3)
+	geom.TileEvenVertically(tiles, r)
+	require.Equal(t, tiles, slices.Collect(geom.TiledEvenVertically(3, r)))
+
+	geom.TileEvenHorizontally(tiles, r)
+	require.Equal(t, tiles, slices.Collect(geom.TiledEvenHorizontally(3, r)))
+}
+
+func TestTileEmpty(t *testing.T) {
+	tilers := map[string]func([]geom.Rect[int], geom.Rect[int]){
+		"right-then-down":    geom.TileRightThenDown[int],
+		"two-thirds-sidebar": geom.TileTwoThirdsSidebar[int],
+		"even-vertically":    geom.TileEvenVertically[int],
+		"even-horizontally":  geom.TileEvenHorizontally[int],
+	}
+	for name, tile := range tilers {
+		t.Run(name, func(t *testing.T) {
+			for _, r := range []geom.Rect[int]{{}, geom.Rt(0, 0, 90, 0), geom.Rt(0, 0, 0, 60)} {
+				tiles := make([]geom.Rect[int], 3)
+				tile(tiles, r)
+				for _, tile := range tiles {
+					require.True(t, tile.Empty(), "%v in %v", tile, r)
+				}
+			}
+		})
+	}
+}
+
+func TestTileColumns(t *testing.T) {
+	r := geom.Rt(0, 0, 60, 30)
+	tiles := make([]geom.Rect[int], 6)
+	geom.TileColumns(tiles, r, 2)
+	require.Equal(t, []geom.Rect[int]{
+		geom.Rt(0, 0, 30, 10),
+		geom.Rt(0, 10, 30, 20),
+		geom.Rt(0, 20, 30, 30),
+		geom.Rt(30, 0, 60, 10),
+		geom.Rt(30, 10, 60, 20),
+		geom.Rt(30, 20, 60, 30),
+	}, tiles)
+
+	tiles = make([]geom.Rect[int], 4)
+	geom.TileColumns(tiles, r, 3)
+	require.Equal(t, []geom.Rect[int]{
+		geom.Rt(0, 0, 20, 15),
+		geom.Rt(0, 15, 20, 30),
+		geom.Rt(20, 0, 40, 30),
+		geom.Rt(40, 0, 60, 30),
+	}, tiles)
+
+	for _, cols := range []int{1, 2, 3, 4, 5, 6} {
+		tiles := make([]geom.Rect[int], 5)
+		geom.TileColumns(tiles, geom.Rt(0, 0, 60, 60), cols)
+		var union geom.Rect[int]
+		var area int
+		for _, tile := range tiles {
+			union = union.Union(tile)
+			area += tile.Area()
+		}
+		require.Equal(t, geom.Rt(0, 0, 60, 60), union, "cols: %v", cols)
+		require.Equal(t, 60*60, area, "cols: %v", cols)
+	}
+
+	for _, cols := range []int{0, -1} {
+		tiles := make([]geom.Rect[int], 3)
+		geom.TileColumns(tiles, r, cols)
+		require.Equal(t, slices.Collect(geom.TiledEvenVertically(3, r)), tiles)
+	}
+	require.Empty(t, slices.Collect(geom.TiledColumns(0, r, 0)))
+}
+
+func TestRowColIter(t *testing.T) {
+	r := geom.Rt(0, 0, 90, 60)
+
+	tiles := make([]geom.Rect[int], 3)
+	geom.TileEvenVertically(tiles, r)
+	for i, row := range r.RowIter(3) {
+		require.Equal(t, tiles[i], row)
+	}
+
+	geom.TileEvenHorizontally(tiles, r)
+	for i, col := range r.ColIter(3) {
+		require.Equal(t, tiles[i], col)
+	}
+}
+
+func TestQuadrant(t *testing.T) {
+	r := geom.Rt(0, 0, 100, 50)
+	q := geom.TileQuadrants(r)
+	require.Equal(t, [...]geom.Rect[int]{
+		geom.Rt(0, 0, 50, 25),
+		geom.Rt(50, 0, 100, 25),
+		geom.Rt(0, 25, 50, 50),
+		geom.Rt(50, 25, 100, 50),
+	}, q)
+
+	var union geom.Rect[int]
+	for i := range 4 {
+		require.Equal(t, q[i], r.Quadrant(i))
+		union = union.Union(r.Quadrant(i))
+	}
+	require.Equal(t, r, union)
+}
+
+func TestTileRightThenDownFew(t *testing.T) {
+	r := geom.Rt(0, 0, 64, 64)
+	tiles := make([]geom.Rect[int], 2)
+
+	geom.TileRightThenDown(tiles[:0], r)
+	require.Equal(t, make([]geom.Rect[int], 2), tiles)
+
+	geom.TileRightThenDown(tiles[:1], r)
+	require.Equal(t, []geom.Rect[int]{r, {}}, tiles)
+
+	geom.TileRightThenDown(tiles, r)
+	require.Equal(t, []geom.Rect[int]{geom.Rt(0, 0, 32, 64), geom.Rt(32, 0, 64, 64)}, tiles)
+}
+
+func TestTileRightThenDown(t *testing.T) {
+	r := geom.Rt(0, 0, 64, 64)
+	tiles := make([]geom.Rect[int], 4)
+	geom.TileRightThenDown(tiles, r)
+	require.Equal(t, []geom.Rect[int]{
+		geom.Rt(0, 0, 32, 64),
+		geom.Rt(32, 0, 64, 32),
+		geom.Rt(32, 32, 48, 64),
+		geom.Rt(48, 32, 64, 64),
+	}, tiles)
+}
+
+func TestSplitN(t *testing.T) {
+	landscape := geom.Rt(0, 0, 90, 60)
+	require.Equal(t, []geom.Rect[int]{
+		geom.Rt(0, 0, 30, 60),
+		geom.Rt(30, 0, 60, 60),
+		geom.Rt(60, 0, 90, 60),
+	}, landscape.SplitN(3))
+
+	portrait := geom.Rt(0, 0, 60, 90)
+	require.Equal(t, []geom.Rect[int]{
+		geom.Rt(0, 0, 60, 30),
+		geom.Rt(0, 30, 60, 60),
+		geom.Rt(0, 60, 60, 90),
+	}, portrait.SplitN(3))
+}
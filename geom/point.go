@@ -2,47 +2,62 @@ package geom
 
 import (
 	"image"
+	"math"
 
 	"golang.org/x/exp/constraints"
 )
 
+// A Point is an X, Y coordinate pair. The axes increase right and
+// down.
 type Point[T Scalar] struct {
 	X, Y T
 }
 
+// Pt is shorthand for Point[T]{X, Y}.
 func Pt[T Scalar](X, Y T) Point[T] {
 	return Point[T]{X, Y}
 }
 
+// FromImagePoint converts an image.Point to a Point[int].
 func FromImagePoint(p image.Point) Point[int] {
 	return Pt(p.X, p.Y)
 }
 
+// PConv converts a Point[In] to a Point[Out] with possible loss of
+// precision.
 func PConv[Out Scalar, In Scalar](p Point[In]) Point[Out] {
 	return Pt(Out(p.X), Out(p.Y))
 }
 
+// Add returns the vector p+q.
 func (p Point[T]) Add(q Point[T]) Point[T] {
 	return Point[T]{p.X + q.X, p.Y + q.Y}
 }
 
+// Sub returns the vector p-q.
 func (p Point[T]) Sub(q Point[T]) Point[T] {
 	return Point[T]{p.X - q.X, p.Y - q.Y}
 }
 
+// Mul returns the vector p*k.
 func (p Point[T]) Mul(k T) Point[T] {
 	return Point[T]{p.X * k, p.Y * k}
 }
 
+// Div returns the vector p/k.
 func (p Point[T]) Div(k T) Point[T] {
 	return Point[T]{p.X / k, p.Y / k}
 }
 
+// In returns true if p is inside of r. Like r itself, the check is
+// inclusive of r.Min and exclusive of r.Max.
 func (p Point[T]) In(r Rect[T]) bool {
 	return r.Min.X <= p.X && p.X < r.Max.X &&
 		r.Min.Y <= p.Y && p.Y < r.Max.Y
 }
 
+// Mod returns the point q in r such that p.X-q.X is a multiple of r's
+// width and p.Y-q.Y is a multiple of r's height.
 func Mod[T constraints.Integer](p Point[T], r Rect[T]) Point[T] {
 	w, h := r.Dx(), r.Dy()
 	p = p.Sub(r.Min)
@@ -57,14 +72,49 @@ func Mod[T constraints.Integer](p Point[T], r Rect[T]) Point[T] {
 	return p.Add(r.Min)
 }
 
+// IsZero returns true if p is the zero Point.
 func (p Point[T]) IsZero() bool {
 	return (p.X == 0) && (p.Y == 0)
 }
 
+// Distance returns the Euclidean distance between p and q. The
+// calculation is done in floating point regardless of T, so the
+// result is not truncated for integer types.
+func (p Point[T]) Distance(q Point[T]) float64 {
+	dx := float64(p.X) - float64(q.X)
+	dy := float64(p.Y) - float64(q.Y)
+	return math.Hypot(dx, dy)
+}
+
+// DistanceSq returns the square of the Euclidean distance between p
+// and q. It avoids the square root needed by [Point.Distance], which
+// makes it cheaper for comparing distances, but it can overflow for
+// integer types if p and q are very far apart.
+func (p Point[T]) DistanceSq(q Point[T]) T {
+	d := p.Sub(q)
+	return d.X*d.X + d.Y*d.Y
+}
+
+// ImagePoint converts p to an image.Point, truncating the coordinates
+// if necessary.
 func (p Point[T]) ImagePoint() image.Point {
 	return image.Pt(int(p.X), int(p.Y))
 }
 
+// LerpPoint linearly interpolates between a and b. A t of 0 returns
+// a and a t of 1 returns b. Values of t outside of [0, 1]
+// extrapolate.
+func LerpPoint[T Scalar](a, b Point[T], t float64) Point[T] {
+	return Pt(lerp(a.X, b.X, t), lerp(a.Y, b.Y, t))
+}
+
+func lerp[T Scalar](a, b T, t float64) T {
+	return T(float64(a) + (float64(b)-float64(a))*t)
+}
+
+// Min returns a point made up of the smallest X and smallest Y
+// coordinates of all of the given points. It panics if no points are
+// given.
 func Min[T Scalar](points ...Point[T]) Point[T] {
 	r := points[0]
 	for _, p := range points[1:] {
@@ -78,6 +128,9 @@ func Min[T Scalar](points ...Point[T]) Point[T] {
 	return r
 }
 
+// Max returns a point made up of the largest X and largest Y
+// coordinates of all of the given points. It panics if no points are
+// given.
 func Max[T Scalar](points ...Point[T]) Point[T] {
 	r := points[0]
 	for _, p := range points[1:] {
@@ -0,0 +1,76 @@
+package geom_test
+
+import (
+	"cmp"
+	"image"
+	"math"
+	"slices"
+	"testing"
+
+	"deedles.dev/ximage/geom"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPt(t *testing.T) {
+	require.Equal(t, geom.Point[int]{X: 1, Y: 2}, geom.Pt(1, 2))
+	require.Equal(t, geom.Point[float32]{X: 1.5, Y: -2}, geom.Pt[float32](1.5, -2))
+	require.Equal(t, image.Pt(3, 4), geom.Pt(3, 4).ImagePoint())
+}
+
+func TestRt(t *testing.T) {
+	require.Equal(t, geom.Rect[int]{Min: geom.Pt(1, 2), Max: geom.Pt(3, 4)}, geom.Rt(1, 2, 3, 4))
+	require.Equal(t, geom.Rt(1, 2, 3, 4), geom.Rt(3, 4, 1, 2))
+	require.Equal(t, image.Rect(1, 2, 3, 4), geom.Rt(1, 2, 3, 4).ImageRect())
+}
+
+func TestPointArithmetic(t *testing.T) {
+	p, q := geom.Pt(6, 8), geom.Pt(2, -4)
+	require.Equal(t, geom.Pt(8, 4), p.Add(q))
+	require.Equal(t, geom.Pt(4, 12), p.Sub(q))
+	require.Equal(t, geom.Pt(12, 16), p.Mul(2))
+	require.Equal(t, geom.Pt(3, 4), p.Div(2))
+
+	r := geom.Rt(0, 0, 10, 10)
+	require.True(t, p.In(r))
+	require.True(t, r.Min.In(r))
+	require.False(t, r.Max.In(r))
+	require.False(t, q.In(r))
+
+	require.Equal(t, geom.Pt(2, 1), geom.Mod(geom.Pt(12, -9), r))
+	require.Equal(t, geom.Pt(2, -4), geom.Min(p, q))
+	require.Equal(t, geom.Pt(6, 8), geom.Max(p, q))
+}
+
+func TestDistance(t *testing.T) {
+	require.Equal(t, 5.0, geom.Pt(0, 0).Distance(geom.Pt(3, 4)))
+	require.Equal(t, 5.0, geom.Pt(3, 4).Distance(geom.Pt(0, 0)))
+	require.Equal(t, 0.0, geom.Pt(7, 7).Distance(geom.Pt(7, 7)))
+	require.InDelta(t, 1.5, geom.Pt(0.5, 1).Distance(geom.Pt(2.0, 1)), 1e-12)
+}
+
+func TestDistanceSq(t *testing.T) {
+	require.Equal(t, 25, geom.Pt(0, 0).DistanceSq(geom.Pt(3, 4)))
+	require.Equal(t, 25, geom.Pt(3, 4).DistanceSq(geom.Pt(0, 0)))
+	require.Equal(t, uint(25), geom.Pt[uint](0, 4).DistanceSq(geom.Pt[uint](3, 0)))
+	require.Equal(t, 2.25, geom.Pt(0.5, 1).DistanceSq(geom.Pt(2.0, 1)))
+
+	require.InDelta(t, math.Sqrt2, geom.Pt(0, 0).Distance(geom.Pt(1, 1)), 1e-12)
+
+	click := geom.Pt(10, 10)
+	points := []geom.Point[int]{geom.Pt(0, 0), geom.Pt(12, 9), geom.Pt(20, 20)}
+	closest := slices.MinFunc(points, func(a, b geom.Point[int]) int {
+		return cmp.Compare(a.DistanceSq(click), b.DistanceSq(click))
+	})
+	require.Equal(t, geom.Pt(12, 9), closest)
+}
+
+func TestLerpPoint(t *testing.T) {
+	a, b := geom.Pt(0, 10), geom.Pt(20, 30)
+	require.Equal(t, a, geom.LerpPoint(a, b, 0))
+	require.Equal(t, b, geom.LerpPoint(a, b, 1))
+	require.Equal(t, geom.Pt(5, 15), geom.LerpPoint(a, b, 0.25))
+	require.Equal(t, geom.Pt(-10, 0), geom.LerpPoint(a, b, -0.5))
+
+	u := geom.LerpPoint(geom.Pt[uint](20, 0), geom.Pt[uint](0, 20), 0.25)
+	require.Equal(t, geom.Pt[uint](15, 5), u)
+}
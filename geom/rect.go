@@ -2,6 +2,9 @@ package geom
 
 import (
 	"image"
+	"math"
+
+	"golang.org/x/exp/constraints"
 )
 
 // A Rect contains the points with Min.X <= X < Max.X, Min.Y <= Y < Max.Y. It
@@ -25,6 +28,7 @@ func Rt[T Scalar](x0, y0, x1, y1 T) Rect[T] {
 	return Rect[T]{Point[T]{x0, y0}, Point[T]{x1, y1}}
 }
 
+// FromImageRect converts an image.Rectangle to a Rect[int].
 func FromImageRect(r image.Rectangle) Rect[int] {
 	return Rect[int]{
 		Min: FromImagePoint(r.Min),
@@ -32,6 +36,15 @@ func FromImageRect(r image.Rectangle) Rect[int] {
 	}
 }
 
+// RectFromSlice creates a Rect from a flat array of coordinates in
+// the order returned by [Rect.ToSlice].
+func RectFromSlice[T Scalar](s [4]T) Rect[T] {
+	return Rect[T]{
+		Min: Pt(s[0], s[1]),
+		Max: Pt(s[2], s[3]),
+	}
+}
+
 // RConv converts a Rect[In] to a Rect[Out] with possible loss of precision.
 func RConv[Out Scalar, In Scalar](r Rect[In]) Rect[Out] {
 	return Rect[Out]{
@@ -40,14 +53,34 @@ func RConv[Out Scalar, In Scalar](r Rect[In]) Rect[Out] {
 	}
 }
 
+// Dx returns the width of r. It is negative if r is not canonical.
 func (r Rect[T]) Dx() T {
 	return r.Max.X - r.Min.X
 }
 
+// Dy returns the height of r. It is negative if r is not canonical.
 func (r Rect[T]) Dy() T {
 	return r.Max.Y - r.Min.Y
 }
 
+// AbsDx returns the width of r. Unlike [Rect.Dx], the result is never
+// negative, even if r is not canonical.
+func (r Rect[T]) AbsDx() T {
+	return r.Canon().Dx()
+}
+
+// AbsDy returns the height of r. Unlike [Rect.Dy], the result is
+// never negative, even if r is not canonical.
+func (r Rect[T]) AbsDy() T {
+	return r.Canon().Dy()
+}
+
+// Area returns the area of r, equivalent to r.Dx() * r.Dy().
+func (r Rect[T]) Area() T {
+	return r.Dx() * r.Dy()
+}
+
+// Size returns the width and height of r as a Point.
 func (r Rect[T]) Size() Point[T] {
 	return Point[T]{
 		r.Max.X - r.Min.X,
@@ -55,6 +88,7 @@ func (r Rect[T]) Size() Point[T] {
 	}
 }
 
+// Add returns r translated by p.
 func (r Rect[T]) Add(p Point[T]) Rect[T] {
 	return Rect[T]{
 		Point[T]{r.Min.X + p.X, r.Min.Y + p.Y},
@@ -62,6 +96,7 @@ func (r Rect[T]) Add(p Point[T]) Rect[T] {
 	}
 }
 
+// Sub returns r translated by -p.
 func (r Rect[T]) Sub(p Point[T]) Rect[T] {
 	return Rect[T]{
 		Point[T]{r.Min.X - p.X, r.Min.Y - p.Y},
@@ -69,10 +104,31 @@ func (r Rect[T]) Sub(p Point[T]) Rect[T] {
 	}
 }
 
+// Translate returns r shifted by dx horizontally and dy vertically.
+// It is equivalent to r.Add(Pt(dx, dy)).
+func (r Rect[T]) Translate(dx, dy T) Rect[T] {
+	return r.Add(Pt(dx, dy))
+}
+
+// AtOrigin returns a rectangle with the same size as r but with its
+// Min at the zero Point. This is useful for converting a rectangle
+// positioned somewhere on screen to the bounds of an image that
+// should be drawn in it.
+func (r Rect[T]) AtOrigin() Rect[T] {
+	return Rect[T]{Max: r.Size()}
+}
+
+// Inset returns r shrunk by n on every side. A negative n grows r
+// instead. If r is too small to be shrunk by n along either axis, it
+// is collapsed to zero size about its center along that axis rather
+// than being given negative dimensions. For different amounts along
+// each axis, see [Rect.Inset2].
 func (r Rect[T]) Inset(n T) Rect[T] {
 	return r.Inset2(Pt(n, n))
 }
 
+// Inset2 is like [Rect.Inset] but shrinks r by n.X on the left and
+// right and by n.Y on the top and bottom.
 func (r Rect[T]) Inset2(n Point[T]) Rect[T] {
 	if r.Dx() < 2*n.X {
 		r.Min.X = (r.Min.X + r.Max.X) / 2
@@ -91,12 +147,21 @@ func (r Rect[T]) Inset2(n Point[T]) Rect[T] {
 	return r
 }
 
-func (r Rect[T]) Pad(top, bottom, left, right T) Rect[T] {
+// Insets describes a potentially different distance from each edge
+// of a rectangle.
+type Insets[T Scalar] struct {
+	Top, Bottom, Left, Right T
+}
+
+// Pad shrinks r inwards by the distances specified by ins. If the
+// insets are larger than r, the result is collapsed to zero size
+// along that dimension.
+func (r Rect[T]) Pad(ins Insets[T]) Rect[T] {
 	r = r.Canon()
-	r.Min.X += left
-	r.Max.X -= right
-	r.Min.Y += top
-	r.Max.Y -= bottom
+	r.Min.X += ins.Left
+	r.Max.X -= ins.Right
+	r.Min.Y += ins.Top
+	r.Max.Y -= ins.Bottom
 	if r.Dx() < 0 {
 		r.Max.X = r.Min.X
 	}
@@ -106,6 +171,19 @@ func (r Rect[T]) Pad(top, bottom, left, right T) Rect[T] {
 	return r
 }
 
+// Unpad is the inverse of [Rect.Pad]. It grows r outwards by the
+// distances specified by ins.
+func (r Rect[T]) Unpad(ins Insets[T]) Rect[T] {
+	r = r.Canon()
+	r.Min.X -= ins.Left
+	r.Max.X += ins.Right
+	r.Min.Y -= ins.Top
+	r.Max.Y += ins.Bottom
+	return r
+}
+
+// Intersect returns the largest rectangle contained by both r and s.
+// If the two rectangles do not overlap, the zero Rect is returned.
 func (r Rect[T]) Intersect(s Rect[T]) Rect[T] {
 	if r.Min.X < s.Min.X {
 		r.Min.X = s.Min.X
@@ -125,6 +203,9 @@ func (r Rect[T]) Intersect(s Rect[T]) Rect[T] {
 	return r
 }
 
+// Union returns the smallest rectangle that contains both r and s.
+// Empty rectangles are ignored, so if one of the two is empty, the
+// other is returned unchanged.
 func (r Rect[T]) Union(s Rect[T]) Rect[T] {
 	if r.Empty() {
 		return s
@@ -147,20 +228,43 @@ func (r Rect[T]) Union(s Rect[T]) Rect[T] {
 	return r
 }
 
+// ExpandToContain returns the smallest rectangle that contains both r
+// and other. It is equivalent to [Rect.Union], including that empty
+// rectangles are ignored, but reads more clearly when accumulating a
+// bounding box:
+//
+//	var bounds geom.Rect[int]
+//	for _, tile := range tiles {
+//		bounds = bounds.ExpandToContain(tile)
+//	}
+func (r Rect[T]) ExpandToContain(other Rect[T]) Rect[T] {
+	return r.Union(other)
+}
+
+// Empty returns true if r contains no points, meaning that its width
+// or height is less than or equal to zero. Rectangles that are not
+// canonical are always empty.
 func (r Rect[T]) Empty() bool {
 	return r.Min.X >= r.Max.X || r.Min.Y >= r.Max.Y
 }
 
+// Eq returns true if r and s contain the same set of points. All
+// empty rectangles are considered equal.
 func (r Rect[T]) Eq(s Rect[T]) bool {
 	return r == s || r.Empty() && s.Empty()
 }
 
+// Overlaps returns true if r and s have a non-empty intersection. It
+// is cheaper than checking the result of [Rect.Intersect] and has the
+// same semantics as image.Rectangle.Overlaps.
 func (r Rect[T]) Overlaps(s Rect[T]) bool {
 	return !r.Empty() && !s.Empty() &&
 		r.Min.X < s.Max.X && s.Min.X < r.Max.X &&
 		r.Min.Y < s.Max.Y && s.Min.Y < r.Max.Y
 }
 
+// In returns true if every point in r is also in s. An empty
+// rectangle is in every other rectangle.
 func (r Rect[T]) In(s Rect[T]) bool {
 	if r.Empty() {
 		return true
@@ -169,6 +273,8 @@ func (r Rect[T]) In(s Rect[T]) bool {
 		s.Min.Y <= r.Min.Y && r.Max.Y <= s.Max.Y
 }
 
+// Canon returns the canonical version of r, swapping the minimum and
+// maximum coordinates if necessary so that it is well-formed.
 func (r Rect[T]) Canon() Rect[T] {
 	if r.Max.X < r.Min.X {
 		r.Min.X, r.Max.X = r.Max.X, r.Min.X
@@ -179,6 +285,37 @@ func (r Rect[T]) Canon() Rect[T] {
 	return r
 }
 
+// TopLeft returns the top-left corner of r, r.Min.
+func (r Rect[T]) TopLeft() Point[T] {
+	return r.Min
+}
+
+// TopRight returns the top-right corner of r.
+func (r Rect[T]) TopRight() Point[T] {
+	return Pt(r.Max.X, r.Min.Y)
+}
+
+// BottomLeft returns the bottom-left corner of r.
+func (r Rect[T]) BottomLeft() Point[T] {
+	return Pt(r.Min.X, r.Max.Y)
+}
+
+// BottomRight returns the bottom-right corner of r, r.Max.
+func (r Rect[T]) BottomRight() Point[T] {
+	return r.Max
+}
+
+// Corners returns the four corners of r in clockwise order starting
+// from the top-left: top-left, top-right, bottom-right, bottom-left.
+func (r Rect[T]) Corners() [4]Point[T] {
+	return [...]Point[T]{
+		r.TopLeft(),
+		r.TopRight(),
+		r.BottomRight(),
+		r.BottomLeft(),
+	}
+}
+
 // Center returns the point at the middle of r.
 func (r Rect[T]) Center() Point[T] {
 	return r.Min.Add(r.Max).Div(2)
@@ -196,6 +333,110 @@ func (r Rect[T]) CenterAt(p Point[T]) Rect[T] {
 	)
 }
 
+// Scale returns r with its width multiplied by sx and its height
+// multiplied by sy. The result is scaled about the point returned by
+// [Rect.Center] so that the center of r does not move, though for
+// integer types the true center may shift by half a unit when a
+// dimension of r is odd. Unlike [Rect.Resize], which keeps r.Min
+// fixed in place, this is suitable for zooming a rectangle in place.
+func (r Rect[T]) Scale(sx, sy T) Rect[T] {
+	c := r.Center()
+	return Rect[T]{
+		Min: Pt(c.X-(c.X-r.Min.X)*sx, c.Y-(c.Y-r.Min.Y)*sy),
+		Max: Pt(c.X+(r.Max.X-c.X)*sx, c.Y+(r.Max.Y-c.Y)*sy),
+	}
+}
+
+// NormalizedPoint maps p from the coordinate space of r to one in
+// which r.Min is at (0, 0) and r.Max is at (1, 1). If r has zero
+// width or height, the corresponding coordinate of the result is 0.
+func (r Rect[T]) NormalizedPoint(p Point[T]) Point[float64] {
+	var n Point[float64]
+	if dx := r.Dx(); dx != 0 {
+		n.X = (float64(p.X) - float64(r.Min.X)) / float64(dx)
+	}
+	if dy := r.Dy(); dy != 0 {
+		n.Y = (float64(p.Y) - float64(r.Min.Y)) / float64(dy)
+	}
+	return n
+}
+
+// AbsolutePoint is the inverse of [Rect.NormalizedPoint]. It maps p
+// from the normalized coordinate space of r back to the coordinate
+// space that r itself is in.
+func (r Rect[T]) AbsolutePoint(p Point[float64]) Point[T] {
+	return Pt(
+		T(float64(r.Min.X)+p.X*float64(r.Dx())),
+		T(float64(r.Min.Y)+p.Y*float64(r.Dy())),
+	)
+}
+
+// Clamp returns p with each of its coordinates clamped to the
+// canonical form of r. For integer types, the result is always a
+// point inside of r, meaning that the maximum coordinates are
+// r.Max.X-1 and r.Max.Y-1. For floating point types, which have no
+// such last point, r.Max itself is used. If r is empty along an axis,
+// the corresponding coordinate is set to that of r.Min.
+func (r Rect[T]) Clamp(p Point[T]) Point[T] {
+	r = r.Canon()
+	last := r.Max
+	if T(1)/2 == 0 {
+		last = last.Sub(Pt[T](1, 1))
+	}
+
+	return Pt(
+		max(r.Min.X, min(p.X, last.X)),
+		max(r.Min.Y, min(p.Y, last.Y)),
+	)
+}
+
+// Lerp linearly interpolates between a and b, such as for animating
+// a transition from one layout to another. A t of 0 returns a and a t
+// of 1 returns b. Values of t outside of [0, 1] extrapolate.
+func Lerp[T Scalar](a, b Rect[T], t float64) Rect[T] {
+	return Rect[T]{
+		Min: LerpPoint(a.Min, b.Min, t),
+		Max: LerpPoint(a.Max, b.Max, t),
+	}
+}
+
+// Reflect returns r mirrored across the center of axis both
+// horizontally and vertically.
+func (r Rect[T]) Reflect(axis Rect[T]) Rect[T] {
+	return r.ReflectX(axis).ReflectY(axis)
+}
+
+// ReflectX returns r mirrored horizontally across the vertical line
+// through the center of axis.
+func (r Rect[T]) ReflectX(axis Rect[T]) Rect[T] {
+	sum := axis.Min.X + axis.Max.X
+	r.Min.X, r.Max.X = sum-r.Max.X, sum-r.Min.X
+	return r
+}
+
+// ReflectY returns r mirrored vertically across the horizontal line
+// through the center of axis.
+func (r Rect[T]) ReflectY(axis Rect[T]) Rect[T] {
+	sum := axis.Min.Y + axis.Max.Y
+	r.Min.Y, r.Max.Y = sum-r.Max.Y, sum-r.Min.Y
+	return r
+}
+
+// RectDiff returns the four rectangles that make up the frame between
+// outer and inner, in the order top, bottom, left, right. The top and
+// bottom strips span the full width of outer, while the left and
+// right strips span only the height of inner. inner is assumed to be
+// entirely inside of outer.
+func RectDiff[T Scalar](outer, inner Rect[T]) [4]Rect[T] {
+	outer, inner = outer.Canon(), inner.Canon()
+	return [...]Rect[T]{
+		{Min: outer.Min, Max: Pt(outer.Max.X, inner.Min.Y)},
+		{Min: Pt(outer.Min.X, inner.Max.Y), Max: outer.Max},
+		{Min: Pt(outer.Min.X, inner.Min.Y), Max: Pt(inner.Min.X, inner.Max.Y)},
+		{Min: Pt(inner.Max.X, inner.Min.Y), Max: Pt(outer.Max.X, inner.Max.Y)},
+	}
+}
+
 // ClosestIn returns r shifted to be inside of s at the closest
 // possible point to its starting position. If r is already entirely
 // inside of s, r is returned unchanged. If r can not fit entirely
@@ -228,20 +469,131 @@ func (r Rect[T]) ClosestIn(s Rect[T]) Rect[T] {
 	return r
 }
 
+// Resize returns a rectangle of the given size with the same top-left
+// corner, r.Min, as r.
 func (r Rect[T]) Resize(size Point[T]) Rect[T] {
 	return Rect[T]{Min: r.Min, Max: r.Min.Add(size)}
 }
 
+// FitTo resizes r to size and then shrinks it along one dimension so
+// that it keeps its original aspect ratio. The top-left corner of r
+// is unchanged.
 func (r Rect[T]) FitTo(size Point[T]) Rect[T] {
 	aspect := r.Aspect()
 	r.Max = r.Min.Add(size)
 	return r.WithAspect(aspect)
 }
 
+// Aspect returns the ratio of the width of r to its height.
 func (r Rect[T]) Aspect() float64 {
 	return float64(r.Dx()) / float64(r.Dy())
 }
 
+// FitInside returns the largest rectangle with the same aspect ratio
+// as r that fits entirely inside of outer, centered in outer. This is
+// the letterboxing that is typically used to display an image in an
+// area of a different shape. For integer types, the dimension that
+// does not match outer is rounded down and the result is offset
+// towards outer.Min when it can not be centered exactly. If r or
+// outer is empty along either axis, the zero Rect is returned.
+func (r Rect[T]) FitInside(outer Rect[T]) Rect[T] {
+	r, outer = r.Canon(), outer.Canon()
+	if r.Empty() || outer.Empty() {
+		return Rect[T]{}
+	}
+
+	matchX := float64(outer.Dx())*float64(r.Dy()) <= float64(outer.Dy())*float64(r.Dx())
+	size := r.fitSize(outer, matchX, func(v float64) T { return T(v) })
+	size = Pt(min(size.X, outer.Dx()), min(size.Y, outer.Dy()))
+	return centeredIn(outer, size)
+}
+
+// FitOutside returns the smallest rectangle with the same aspect
+// ratio as r that entirely covers outer, centered on outer. This is
+// the cropping that is typically used to fill an area with an image
+// of a different shape, and is the counterpart to [Rect.FitInside].
+// For integer types, the dimension that does not match outer is
+// rounded up. If r or outer is empty along either axis, the zero Rect
+// is returned.
+func (r Rect[T]) FitOutside(outer Rect[T]) Rect[T] {
+	r, outer = r.Canon(), outer.Canon()
+	if r.Empty() || outer.Empty() {
+		return Rect[T]{}
+	}
+
+	matchX := float64(outer.Dx())*float64(r.Dy()) >= float64(outer.Dy())*float64(r.Dx())
+	size := r.fitSize(outer, matchX, ceil[T])
+	size = Pt(max(size.X, outer.Dx()), max(size.Y, outer.Dy()))
+	return centeredIn(outer, size)
+}
+
+// fitSize returns the size of a rectangle with the aspect ratio of r
+// that has the same width as outer if matchX is true or the same
+// height if it is not. The other dimension is rounded by round.
+func (r Rect[T]) fitSize(outer Rect[T], matchX bool, round func(float64) T) Point[T] {
+	if matchX {
+		return Pt(outer.Dx(), round(float64(r.Dy())*float64(outer.Dx())/float64(r.Dx())))
+	}
+	return Pt(round(float64(r.Dx())*float64(outer.Dy())/float64(r.Dy())), outer.Dy())
+}
+
+// centeredIn returns a rectangle of the given size centered in outer.
+// Offsets are computed from outer.Min so that integer rounding never
+// depends on the sign or parity of outer's coordinates.
+func centeredIn[T Scalar](outer Rect[T], size Point[T]) Rect[T] {
+	tl := outer.Min.Add(outer.Size().Sub(size).Div(2))
+	return Rect[T]{Min: tl, Max: tl.Add(size)}
+}
+
+// ceil converts v to T, rounding up if T is an integer type.
+func ceil[T Scalar](v float64) T {
+	t := T(v)
+	if (T(1)/2 == 0) && (float64(t) < v) {
+		t++
+	}
+	return t
+}
+
+// ConstrainAspect returns a rectangle with the aspect ratio
+// wNum:hDen and approximately the same area as r. The result shares
+// r.Min with r. If either part of the ratio is not positive, an empty
+// rectangle at r.Min is returned.
+func (r Rect[T]) ConstrainAspect(wNum, hDen T) Rect[T] {
+	r = r.Canon()
+	if wNum <= 0 || hDen <= 0 {
+		return Rect[T]{Min: r.Min, Max: r.Min}
+	}
+
+	area := float64(r.Dx()) * float64(r.Dy())
+	aspect := float64(wNum) / float64(hDen)
+	return r.Resize(Pt(
+		T(math.Sqrt(area*aspect)),
+		T(math.Sqrt(area/aspect)),
+	))
+}
+
+// AspectRatio returns the aspect ratio of r as a width to height
+// ratio in lowest terms, such as 16:9. For a floating point aspect
+// ratio, see [Rect.Aspect].
+func AspectRatio[T constraints.Integer](r Rect[T]) (w, h T) {
+	r = r.Canon()
+	w, h = r.Dx(), r.Dy()
+	d := gcd(w, h)
+	if d == 0 {
+		return 0, 0
+	}
+	return w / d, h / d
+}
+
+func gcd[T constraints.Integer](a, b T) T {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// WithAspect returns the largest rectangle with the given aspect
+// ratio that fits in r and shares its top-left corner.
 func (r Rect[T]) WithAspect(aspect float64) Rect[T] {
 	if r.Aspect() > aspect {
 		return r.Resize(Pt(T(float64(r.Dy())*aspect), r.Dy()))
@@ -249,6 +601,8 @@ func (r Rect[T]) WithAspect(aspect float64) Rect[T] {
 	return r.Resize(Pt(r.Dx(), T(float64(r.Dx())/aspect)))
 }
 
+// PropShift maps r from the coordinate space of from to that of to,
+// scaling its position and size proportionally.
 func (r Rect[T]) PropShift(to, from Rect[T]) Rect[T] {
 	ratio := to.Size()
 	ratio.X /= from.Dx()
@@ -269,13 +623,22 @@ func (r Rect[T]) PropShift(to, from Rect[T]) Rect[T] {
 	}
 }
 
+// IsZero returns true if r is the zero Rect.
 func (r Rect[T]) IsZero() bool {
 	return r.Min.IsZero() && r.Max.IsZero()
 }
 
+// ImageRect converts r to an image.Rectangle, truncating the
+// coordinates if necessary.
 func (r Rect[T]) ImageRect() image.Rectangle {
 	return image.Rectangle{
 		Min: r.Min.ImagePoint(),
 		Max: r.Max.ImagePoint(),
 	}
 }
+
+// ToSlice returns the coordinates of r as a flat array in the order
+// [Min.X, Min.Y, Max.X, Max.Y]. This is useful for serialization.
+func (r Rect[T]) ToSlice() [4]T {
+	return [...]T{r.Min.X, r.Min.Y, r.Max.X, r.Max.Y}
+}
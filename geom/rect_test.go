@@ -0,0 +1,393 @@
+package geom_test
+
+import (
+	"testing"
+
+	"deedles.dev/ximage/geom"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRect(t *testing.T) {
+	r := geom.Rt(30, 40, 10, 20)
+	require.Equal(t, geom.Rect[int]{Min: geom.Pt(10, 20), Max: geom.Pt(30, 40)}, r)
+	require.Equal(t, 20, r.Dx())
+	require.Equal(t, 20, r.Dy())
+	require.Equal(t, geom.Pt(20, 20), r.Size())
+	require.Equal(t, geom.Rt(15, 15, 35, 35), r.Add(geom.Pt(5, -5)))
+	require.Equal(t, r, r.Add(geom.Pt(5, -5)).Sub(geom.Pt(5, -5)))
+	require.Equal(t, geom.Pt(20, 30), r.Center())
+	require.Equal(t, geom.Rt(-10, -10, 10, 10), r.CenterAt(geom.Pt(0, 0)))
+
+	flipped := geom.Rect[int]{Min: r.Max, Max: r.Min}
+	require.Equal(t, r, flipped.Canon())
+
+	require.True(t, geom.Rt(15, 25, 25, 35).In(r))
+	require.True(t, r.In(r))
+	require.False(t, geom.Rt(0, 25, 25, 35).In(r))
+	require.True(t, geom.Rect[int]{}.In(r))
+
+	require.Equal(t, r, geom.FromImageRect(r.ImageRect()))
+	require.True(t, geom.Rect[float64]{}.IsZero())
+	require.True(t, geom.Rt(1, 1, 1, 1).Eq(geom.Rt(5, 6, 5, 6)))
+}
+
+func TestArea(t *testing.T) {
+	require.Equal(t, 200, geom.Rt(10, 20, 30, 30).Area())
+	require.Equal(t, 0, geom.Rt(10, 20, 10, 30).Area())
+	require.Equal(t, 2.5, geom.Rt(0, 0, 0.5, 5).Area())
+}
+
+func TestEmpty(t *testing.T) {
+	require.True(t, geom.Rect[int]{}.Empty())
+	require.True(t, geom.Rt(10, 10, 20, 10).Empty())
+	require.True(t, geom.Rt(10, 10, 10, 20).Empty())
+	require.True(t, geom.Rect[int]{Min: geom.Pt(20, 20), Max: geom.Pt(10, 10)}.Empty())
+	require.False(t, geom.Rt(10, 10, 11, 11).Empty())
+	require.False(t, geom.Rt(0, 0, 0.5, 0.5).Empty())
+}
+
+func TestIntersect(t *testing.T) {
+	tests := []struct {
+		r, s, out geom.Rect[int]
+	}{
+		{geom.Rt(0, 0, 20, 20), geom.Rt(10, 5, 30, 15), geom.Rt(10, 5, 20, 15)},
+		{geom.Rt(0, 0, 20, 20), geom.Rt(5, 5, 10, 10), geom.Rt(5, 5, 10, 10)},
+		{geom.Rt(0, 0, 20, 20), geom.Rt(20, 0, 40, 20), geom.Rect[int]{}},
+		{geom.Rt(10, 10, 20, 20), geom.Rt(30, 30, 40, 40), geom.Rect[int]{}},
+		{geom.Rt(0, 0, 20, 20), geom.Rt(5, 5, 5, 15), geom.Rect[int]{}},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.out, test.r.Intersect(test.s), "%v, %v", test.r, test.s)
+		require.Equal(t, test.out, test.s.Intersect(test.r), "%v, %v", test.s, test.r)
+	}
+}
+
+func TestUnion(t *testing.T) {
+	tests := []struct {
+		r, s, out geom.Rect[int]
+	}{
+		{geom.Rt(0, 0, 20, 20), geom.Rt(10, 5, 30, 15), geom.Rt(0, 0, 30, 20)},
+		{geom.Rt(0, 0, 20, 20), geom.Rt(5, 5, 10, 10), geom.Rt(0, 0, 20, 20)},
+		{geom.Rt(10, 10, 20, 20), geom.Rt(30, 30, 40, 40), geom.Rt(10, 10, 40, 40)},
+		{geom.Rt(10, 10, 20, 20), geom.Rect[int]{}, geom.Rt(10, 10, 20, 20)},
+		{geom.Rt(10, 10, 20, 20), geom.Rt(50, 50, 50, 60), geom.Rt(10, 10, 20, 20)},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.out, test.r.Union(test.s), "%v, %v", test.r, test.s)
+		require.Equal(t, test.out, test.s.Union(test.r), "%v, %v", test.s, test.r)
+	}
+}
+
+func TestOverlaps(t *testing.T) {
+	rects := []geom.Rect[int]{
+		geom.Rt(0, 0, 20, 20),
+		geom.Rt(10, 5, 30, 15),
+		geom.Rt(5, 5, 10, 10),
+		geom.Rt(20, 0, 40, 20),
+		geom.Rt(30, 30, 40, 40),
+		geom.Rt(5, 5, 5, 15),
+		{},
+	}
+	for _, r := range rects {
+		for _, s := range rects {
+			expected := r.ImageRect().Overlaps(s.ImageRect())
+			require.Equal(t, expected, r.Overlaps(s), "%v, %v", r, s)
+			require.Equal(t, !r.Intersect(s).Empty(), r.Overlaps(s), "%v, %v", r, s)
+		}
+	}
+}
+
+func TestExpandToContain(t *testing.T) {
+	tiles := []geom.Rect[int]{
+		geom.Rt(10, 10, 20, 20),
+		geom.Rt(30, 5, 40, 15),
+		geom.Rt(100, 100, 100, 200),
+		geom.Rt(15, 25, 25, 35),
+	}
+
+	var bounds geom.Rect[int]
+	for _, tile := range tiles {
+		bounds = bounds.ExpandToContain(tile)
+	}
+	require.Equal(t, geom.Rt(10, 5, 40, 35), bounds)
+
+	require.Equal(t, tiles[0], tiles[0].ExpandToContain(geom.Rect[int]{}))
+	require.Equal(t, tiles[0], tiles[2].ExpandToContain(tiles[0]))
+}
+
+func TestToSlice(t *testing.T) {
+	r := geom.Rt(1.5, 2, 3, 4.25)
+	s := r.ToSlice()
+	require.Equal(t, [...]float64{1.5, 2, 3, 4.25}, s)
+	require.Equal(t, r, geom.RectFromSlice(s))
+}
+
+func TestTranslate(t *testing.T) {
+	r := geom.Rt(10, 20, 50, 40)
+	require.Equal(t, geom.Rt(15, 10, 55, 30), r.Translate(5, -10))
+	require.Equal(t, r.Add(geom.Pt(5, -10)), r.Translate(5, -10))
+	require.Equal(t, r, r.Translate(0, 0))
+}
+
+func TestAtOrigin(t *testing.T) {
+	r := geom.Rt(10, 20, 50, 40)
+	require.Equal(t, geom.Rt(0, 0, 40, 20), r.AtOrigin())
+	require.Equal(t, r.Size(), r.AtOrigin().Size())
+	require.Equal(t, r.AtOrigin(), r.AtOrigin().AtOrigin())
+	require.Equal(t, geom.Rt(0, 0, 2.5, 1), geom.Rt(-1, -1, 1.5, 0).AtOrigin())
+}
+
+func TestInset(t *testing.T) {
+	r := geom.Rt(10, 10, 50, 30)
+	require.Equal(t, geom.Rt(15, 15, 45, 25), r.Inset(5))
+	require.Equal(t, geom.Rt(5, 5, 55, 35), r.Inset(-5))
+	require.Equal(t, r, r.Inset(-5).Inset(5))
+	require.Equal(t, geom.Rt(25, 20, 35, 20), r.Inset(15))
+	require.Equal(t, geom.Rt(30, 20, 30, 20), r.Inset(25))
+
+	require.Equal(t, geom.Rt(12, 5, 48, 35), r.Inset2(geom.Pt(2, -5)))
+	require.Equal(t, geom.Rt(8, 12, 52, 28), r.Inset2(geom.Pt(-2, 2)))
+	require.Equal(t, r.Inset(3), r.Inset2(geom.Pt(3, 3)))
+}
+
+func TestPad(t *testing.T) {
+	r := geom.Rt(0, 0, 100, 50)
+	ins := geom.Insets[int]{Top: 1, Bottom: 2, Left: 3, Right: 4}
+	require.Equal(t, geom.Rt(3, 1, 96, 48), r.Pad(ins))
+	require.Equal(t, r, r.Pad(ins).Unpad(ins))
+
+	require.Equal(t, 0, r.Pad(geom.Insets[int]{Left: 60, Right: 60}).Dx())
+}
+
+func TestResize(t *testing.T) {
+	r := geom.Rt(10, 20, 30, 40)
+	s := r.Resize(geom.Pt(5, 50))
+	require.Equal(t, r.Min, s.Min)
+	require.Equal(t, 5, s.Dx())
+	require.Equal(t, 50, s.Dy())
+}
+
+func TestFitInside(t *testing.T) {
+	tests := []struct {
+		r, outer, out geom.Rect[int]
+	}{
+		{geom.Rt(0, 0, 16, 9), geom.Rt(0, 0, 160, 160), geom.Rt(0, 35, 160, 125)},
+		{geom.Rt(0, 0, 9, 16), geom.Rt(0, 0, 160, 160), geom.Rt(35, 0, 125, 160)},
+		{geom.Rt(0, 0, 10, 10), geom.Rt(20, 10, 60, 30), geom.Rt(30, 10, 50, 30)},
+		{geom.Rt(5, 5, 25, 15), geom.Rt(0, 0, 40, 20), geom.Rt(0, 0, 40, 20)},
+		{geom.Rt(0, 0, 16, 9), geom.Rt(0, 0, 0, 100), geom.Rect[int]{}},
+		{geom.Rt(0, 0, 16, 9), geom.Rt(0, 0, 100, 0), geom.Rect[int]{}},
+		{geom.Rt(0, 0, 0, 9), geom.Rt(0, 0, 100, 100), geom.Rect[int]{}},
+		{geom.Rt(0, 0, 16, 9), geom.Rt(-7, -3, 8, 20), geom.Rt(-7, 4, 8, 12)},
+		{geom.Rt(0, 0, 3, 3), geom.Rt(-5, -5, 0, 2), geom.Rt(-5, -4, 0, 1)},
+		{geom.Rt(0, 0, 2, 1), geom.Rt(-3, -3, 4, 4), geom.Rt(-3, -1, 4, 2)},
+	}
+	for _, test := range tests {
+		out := test.r.FitInside(test.outer)
+		require.Equal(t, test.out, out, "%v in %v", test.r, test.outer)
+		require.True(t, out.In(test.outer), "%v in %v", test.r, test.outer)
+	}
+
+	for w := 1; w < 50; w++ {
+		for h := 1; h < 50; h++ {
+			outer := geom.Rt(-5, 3, -5+w, 3+h)
+			out := geom.Rt(0, 0, 7, 3).FitInside(outer)
+			require.True(t, out.In(outer), outer)
+			if out.Dx() == outer.Dx() {
+				require.Equal(t, w*3/7, out.Dy(), outer)
+			} else {
+				require.Equal(t, outer.Dy(), out.Dy(), outer)
+				require.Equal(t, h*7/3, out.Dx(), outer)
+			}
+		}
+	}
+
+	f := geom.Rt(0, 0, 4.0, 3.0).FitInside(geom.Rt(-1, -1, 7.0, 3.0))
+	require.InDelta(t, 1.0/3, f.Min.X, 1e-9)
+	require.InDelta(t, 16.0/3, f.Dx(), 1e-9)
+	require.Equal(t, 4.0, f.Dy())
+}
+
+func TestFitOutside(t *testing.T) {
+	tests := []struct {
+		r, outer, out geom.Rect[int]
+	}{
+		{geom.Rt(0, 0, 16, 9), geom.Rt(0, 0, 90, 90), geom.Rt(-35, 0, 125, 90)},
+		{geom.Rt(0, 0, 9, 16), geom.Rt(0, 0, 90, 90), geom.Rt(0, -35, 90, 125)},
+		{geom.Rt(0, 0, 10, 10), geom.Rt(20, 10, 60, 30), geom.Rt(20, 0, 60, 40)},
+		{geom.Rt(5, 5, 25, 15), geom.Rt(0, 0, 40, 20), geom.Rt(0, 0, 40, 20)},
+		{geom.Rt(0, 0, 16, 9), geom.Rt(0, 0, 0, 100), geom.Rect[int]{}},
+		{geom.Rt(0, 0, 0, 9), geom.Rt(0, 0, 100, 100), geom.Rect[int]{}},
+		{geom.Rt(0, 0, 16, 9), geom.Rt(-7, -3, 8, 20), geom.Rt(-20, -3, 21, 20)},
+		{geom.Rt(0, 0, 3, 3), geom.Rt(-5, -5, 0, 2), geom.Rt(-6, -5, 1, 2)},
+	}
+	for _, test := range tests {
+		out := test.r.FitOutside(test.outer)
+		require.Equal(t, test.out, out, "%v over %v", test.r, test.outer)
+		if !out.Empty() {
+			require.True(t, test.outer.In(out), "%v over %v", test.r, test.outer)
+		}
+	}
+
+	for w := 1; w < 50; w++ {
+		for h := 1; h < 50; h++ {
+			outer := geom.Rt(-5, 3, -5+w, 3+h)
+			out := geom.Rt(0, 0, 7, 3).FitOutside(outer)
+			require.True(t, outer.In(out), outer)
+			if out.Dx() == outer.Dx() {
+				require.Equal(t, (w*3+6)/7, out.Dy(), outer)
+			} else {
+				require.Equal(t, outer.Dy(), out.Dy(), outer)
+				require.Equal(t, (h*7+2)/3, out.Dx(), outer)
+			}
+		}
+	}
+}
+
+func TestConstrainAspect(t *testing.T) {
+	r := geom.Rt(10, 10, 70, 70)
+	require.Equal(t, geom.Rt(10, 10, 90, 55), r.ConstrainAspect(16, 9))
+	require.Equal(t, geom.Rt(10, 10, 55, 90), r.ConstrainAspect(9, 16))
+	require.Equal(t, r, r.ConstrainAspect(1, 1))
+	require.Equal(t, geom.Rt(10, 10, 10, 10), r.ConstrainAspect(0, 1))
+
+	f := geom.Rt(0, 0, 4.0, 1.0).ConstrainAspect(1, 4)
+	require.InDelta(t, 1, f.Dx(), 1e-9)
+	require.InDelta(t, 4, f.Dy(), 1e-9)
+}
+
+func TestAspectRatio(t *testing.T) {
+	tests := []struct {
+		r    geom.Rect[int]
+		w, h int
+	}{
+		{geom.Rt(0, 0, 1920, 1080), 16, 9},
+		{geom.Rt(0, 0, 1024, 768), 4, 3},
+		{geom.Rt(5, 5, 55, 55), 1, 1},
+		{geom.Rect[int]{}, 0, 0},
+	}
+	for _, test := range tests {
+		w, h := geom.AspectRatio(test.r)
+		require.Equal(t, test.w, w, test.r)
+		require.Equal(t, test.h, h, test.r)
+	}
+}
+
+func TestAbsDxDy(t *testing.T) {
+	r := geom.Rect[int]{Min: geom.Pt(10, 10), Max: geom.Pt(4, 2)}
+	require.Equal(t, -6, r.Dx())
+	require.Equal(t, 6, r.AbsDx())
+	require.Equal(t, -8, r.Dy())
+	require.Equal(t, 8, r.AbsDy())
+}
+
+func TestCorners(t *testing.T) {
+	r := geom.Rt(10, 20, 50, 40)
+	require.Equal(t, geom.Pt(10, 20), r.TopLeft())
+	require.Equal(t, geom.Pt(50, 20), r.TopRight())
+	require.Equal(t, geom.Pt(10, 40), r.BottomLeft())
+	require.Equal(t, geom.Pt(50, 40), r.BottomRight())
+
+	require.Equal(t, [...]geom.Point[int]{
+		geom.Pt(10, 20),
+		geom.Pt(50, 20),
+		geom.Pt(50, 40),
+		geom.Pt(10, 40),
+	}, r.Corners())
+}
+
+func TestScale(t *testing.T) {
+	r := geom.Rt(10, 10, 30, 20)
+	s := r.Scale(2, 3)
+	require.Equal(t, geom.Rt(0, 0, 40, 30), s)
+	require.Equal(t, r.Center(), s.Center())
+	require.Equal(t, r, r.Scale(1, 1))
+
+	odd := geom.Rt(0, 0, 5, 3)
+	s = odd.Scale(2, 2)
+	require.Equal(t, 10, s.Dx())
+	require.Equal(t, 6, s.Dy())
+	require.True(t, odd.In(s))
+
+	f := geom.Rt(0, 0, 4.0, 2.0)
+	require.Equal(t, geom.Rt(1, 0.5, 3, 1.5), f.Scale(0.5, 0.5))
+}
+
+func TestNormalizedPoint(t *testing.T) {
+	r := geom.Rt(10, 20, 50, 40)
+	require.Equal(t, geom.Pt(0.0, 0.0), r.NormalizedPoint(r.Min))
+	require.Equal(t, geom.Pt(1.0, 1.0), r.NormalizedPoint(r.Max))
+	require.Equal(t, geom.Pt(0.25, 0.5), r.NormalizedPoint(geom.Pt(20, 30)))
+	require.Equal(t, geom.Pt(-0.25, 1.5), r.NormalizedPoint(geom.Pt(0, 50)))
+
+	for _, p := range []geom.Point[int]{r.Min, r.Max, geom.Pt(20, 30), geom.Pt(0, 50)} {
+		require.Equal(t, p, r.AbsolutePoint(r.NormalizedPoint(p)))
+	}
+
+	flat := geom.Rt(10, 20, 10, 40)
+	require.Equal(t, geom.Pt(0.0, 0.5), flat.NormalizedPoint(geom.Pt(30, 30)))
+	require.Equal(t, geom.Pt(10, 30), flat.AbsolutePoint(geom.Pt(0.75, 0.5)))
+
+	u := geom.Rt[uint](10, 10, 20, 20)
+	require.Equal(t, geom.Pt(-1.0, 0.0), u.NormalizedPoint(geom.Pt[uint](0, 10)))
+}
+
+func TestClamp(t *testing.T) {
+	r := geom.Rt(10, 20, 50, 40)
+	require.Equal(t, geom.Pt(30, 30), r.Clamp(geom.Pt(30, 30)))
+	require.Equal(t, geom.Pt(10, 20), r.Clamp(geom.Pt(0, 0)))
+	require.Equal(t, geom.Pt(49, 39), r.Clamp(geom.Pt(100, 100)))
+	require.Equal(t, geom.Pt(49, 20), r.Clamp(geom.Pt(50, 5)))
+	require.Equal(t, geom.Pt(10, 30), geom.Rt(10, 20, 10, 40).Clamp(geom.Pt(30, 30)))
+
+	flipped := geom.Rect[int]{Min: r.Max, Max: r.Min}
+	require.Equal(t, geom.Pt(49, 39), flipped.Clamp(geom.Pt(100, 100)))
+
+	f := geom.Rt(0, 0, 1.0, 1.0)
+	require.Equal(t, geom.Pt(1.0, 0.5), f.Clamp(geom.Pt(2, 0.5)))
+}
+
+func TestLerp(t *testing.T) {
+	a, b := geom.Rt(0, 0, 10, 10), geom.Rt(10, 20, 50, 30)
+	require.Equal(t, a, geom.Lerp(a, b, 0))
+	require.Equal(t, b, geom.Lerp(a, b, 1))
+	require.Equal(t, geom.Rt(5, 10, 30, 20), geom.Lerp(a, b, 0.5))
+	require.Equal(t, geom.Rt(20, 40, 90, 50), geom.Lerp(a, b, 2))
+
+	f := geom.Lerp(geom.Rt(0, 0, 1.0, 1.0), geom.Rt(1, 1, 2.0, 3.0), 0.5)
+	require.Equal(t, geom.Rt(0.5, 0.5, 1.5, 2), f)
+}
+
+func TestReflect(t *testing.T) {
+	axis := geom.Rt(0, 0, 100, 50)
+	r := geom.Rt(10, 5, 30, 15)
+	require.Equal(t, geom.Rt(70, 5, 90, 15), r.ReflectX(axis))
+	require.Equal(t, geom.Rt(10, 35, 30, 45), r.ReflectY(axis))
+	require.Equal(t, geom.Rt(70, 35, 90, 45), r.Reflect(axis))
+	require.Equal(t, r, r.Reflect(axis).Reflect(axis))
+}
+
+func TestRectDiff(t *testing.T) {
+	outer := geom.Rt(0, 0, 100, 80)
+	inner := geom.Rt(10, 20, 70, 50)
+	strips := geom.RectDiff(outer, inner)
+	require.Equal(t, [...]geom.Rect[int]{
+		geom.Rt(0, 0, 100, 20),
+		geom.Rt(0, 50, 100, 80),
+		geom.Rt(0, 20, 10, 50),
+		geom.Rt(70, 20, 100, 50),
+	}, strips)
+
+	union := inner
+	area := inner.Area()
+	for i, s := range strips {
+		require.False(t, s.Overlaps(inner))
+		for _, o := range strips[i+1:] {
+			require.False(t, s.Overlaps(o))
+		}
+		union = union.Union(s)
+		area += s.Area()
+	}
+	require.Equal(t, outer, union)
+	require.Equal(t, outer.Area(), area)
+}
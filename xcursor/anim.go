@@ -0,0 +1,71 @@
+package xcursor
+
+import "time"
+
+// IsAnimated returns true if any of the sizes of the cursor have more
+// than one frame.
+func (c *Cursor) IsAnimated() bool {
+	for _, imgs := range c.Images {
+		if len(imgs) > 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// AnimationFrames returns the frames of the cursor for the given
+// nominal size in the order that they appeared in the file. If the
+// cursor has no images of that size, it returns nil.
+func (c *Cursor) AnimationFrames(size int) []*Image {
+	return c.Images[size]
+}
+
+// TotalDuration returns the sum of the delays of all of the frames of
+// the cursor for the given nominal size.
+func (c *Cursor) TotalDuration(size int) (total time.Duration) {
+	for _, img := range c.Images[size] {
+		total += img.Delay
+	}
+	return total
+}
+
+// FrameIterator tracks playback of a sequence of animation frames.
+type FrameIterator struct {
+	frames []*Image
+	i      int
+}
+
+// NewFrameIterator returns a FrameIterator positioned at the first of
+// the given frames.
+func NewFrameIterator(frames []*Image) *FrameIterator {
+	return &FrameIterator{frames: frames}
+}
+
+// Current returns the current frame. If there are no frames, it
+// returns nil.
+func (f *FrameIterator) Current() *Image {
+	if len(f.frames) == 0 {
+		return nil
+	}
+	return f.frames[f.i]
+}
+
+// Next advances to the next frame, wrapping around to the first after
+// the last.
+func (f *FrameIterator) Next() {
+	if len(f.frames) == 0 {
+		return
+	}
+	f.i = (f.i + 1) % len(f.frames)
+}
+
+// Deadline returns the time at which the current frame, having been
+// shown at now, should be replaced by the next one. If there are
+// fewer than two frames, there is nothing to advance to and the zero
+// time is returned.
+func (f *FrameIterator) Deadline(now time.Time) time.Time {
+	if len(f.frames) < 2 {
+		return time.Time{}
+	}
+	return now.Add(f.frames[f.i].Delay)
+}
@@ -0,0 +1,61 @@
+package xcursor_test
+
+import (
+	"testing"
+	"time"
+
+	"deedles.dev/ximage/xcursor"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnimation(t *testing.T) {
+	c := xcursor.Cursor{
+		Images: map[int][]*xcursor.Image{
+			24: {
+				{NominalSize: 24, Delay: 50 * time.Millisecond},
+			},
+		},
+	}
+	require.False(t, c.IsAnimated())
+	require.Len(t, c.AnimationFrames(24), 1)
+	require.Equal(t, 50*time.Millisecond, c.TotalDuration(24))
+
+	c.Images[32] = []*xcursor.Image{
+		{NominalSize: 32, Delay: 50 * time.Millisecond},
+		{NominalSize: 32, Delay: 100 * time.Millisecond},
+		{NominalSize: 32, Delay: 25 * time.Millisecond},
+	}
+	require.True(t, c.IsAnimated())
+	require.Equal(t, c.Images[32], c.AnimationFrames(32))
+	require.Equal(t, 175*time.Millisecond, c.TotalDuration(32))
+
+	require.Nil(t, c.AnimationFrames(48))
+	require.Zero(t, c.TotalDuration(48))
+}
+
+func TestFrameIterator(t *testing.T) {
+	frames := []*xcursor.Image{
+		{Delay: 50 * time.Millisecond},
+		{Delay: 100 * time.Millisecond},
+		{Delay: 25 * time.Millisecond},
+	}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	f := xcursor.NewFrameIterator(frames)
+	for _, i := range []int{0, 1, 2, 0, 1} {
+		require.Same(t, frames[i], f.Current())
+		require.Equal(t, now.Add(frames[i].Delay), f.Deadline(now))
+		f.Next()
+	}
+
+	f = xcursor.NewFrameIterator(frames[:1])
+	require.Same(t, frames[0], f.Current())
+	require.True(t, f.Deadline(now).IsZero())
+	f.Next()
+	require.Same(t, frames[0], f.Current())
+
+	f = xcursor.NewFrameIterator(nil)
+	require.Nil(t, f.Current())
+	require.True(t, f.Deadline(now).IsZero())
+	f.Next()
+}
@@ -7,7 +7,10 @@ import (
 	"fmt"
 	"image"
 	"io"
+	"iter"
+	"maps"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
@@ -33,7 +36,7 @@ func init() {
 				return nil, err
 			}
 			if len(cur.Images) == 0 {
-				return nil, errors.New("no images in cursor")
+				return nil, ErrNoImages
 			}
 
 			return cur.Images[largest(cur)][0].Image, nil
@@ -44,7 +47,7 @@ func init() {
 				return image.Config{}, err
 			}
 			if len(cur.Images) == 0 {
-				return image.Config{}, errors.New("no images in cursor")
+				return image.Config{}, ErrNoImages
 			}
 
 			largest := cur.Images[largest(cur)]
@@ -62,8 +65,17 @@ func init() {
 // to load a cursor.
 var ErrBadMagic = errors.New("bad magic")
 
+// ErrNoImages indicates that a cursor has no images when at least one
+// is required.
+var ErrNoImages = errors.New("no images in cursor")
+
+// ErrUnsupportedVersion indicates that a cursor file has a version
+// that this package does not know how to decode.
+var ErrUnsupportedVersion = errors.New("unsupported version")
+
 const (
-	fileMagic = 0x72756358 // ASCII "Xcur"
+	fileMagic   = 0x72756358 // ASCII "Xcur"
+	fileVersion = 0x00010000 // 1.0
 )
 
 // Cursor contains information decoded from a Xcursor file.
@@ -86,6 +98,71 @@ const (
 	CommentSubtypeOther
 )
 
+// AddComment adds a comment with the given subtype to the cursor. It
+// returns an error if subtype is not one of the known subtypes.
+func (c *Cursor) AddComment(subtype CommentSubtype, text string) error {
+	switch subtype {
+	case CommentSubtypeCopyright, CommentSubtypeLicense, CommentSubtypeOther:
+	default:
+		return fmt.Errorf("unknown comment subtype: %v", subtype)
+	}
+
+	c.Comments = append(c.Comments, &Comment{
+		Subtype: subtype,
+		Comment: text,
+	})
+	return nil
+}
+
+// AddCopyrightComment adds a copyright comment to the cursor.
+func (c *Cursor) AddCopyrightComment(text string) {
+	c.AddComment(CommentSubtypeCopyright, text)
+}
+
+// AddLicenseComment adds a license comment to the cursor.
+func (c *Cursor) AddLicenseComment(text string) {
+	c.AddComment(CommentSubtypeLicense, text)
+}
+
+// Clone returns a deep copy of c. The returned cursor shares no
+// memory with c, including the pixel data of its images.
+func (c *Cursor) Clone() *Cursor {
+	var clone Cursor
+	if c.Comments != nil {
+		clone.Comments = make([]*Comment, 0, len(c.Comments))
+	}
+	if c.Images != nil {
+		clone.Images = make(map[int][]*Image, len(c.Images))
+	}
+	for _, comment := range c.Comments {
+		comment := *comment
+		clone.Comments = append(clone.Comments, &comment)
+	}
+	for size, imgs := range c.Images {
+		cimgs := make([]*Image, 0, len(imgs))
+		for _, img := range imgs {
+			cimgs = append(cimgs, img.clone())
+		}
+		clone.Images[size] = cimgs
+	}
+	return &clone
+}
+
+// EachImage returns an iterator over the images of the cursor and
+// their nominal sizes. Images are yielded in order of ascending size
+// and then in the order of their frames.
+func (c *Cursor) EachImage() iter.Seq2[int, *Image] {
+	return func(yield func(int, *Image) bool) {
+		for _, size := range slices.Sorted(maps.Keys(c.Images)) {
+			for _, img := range c.Images[size] {
+				if !yield(size, img) {
+					return
+				}
+			}
+		}
+	}
+}
+
 const (
 	tocTypeComment = 0xfffe0001
 	tocTypeImage   = 0xfffd0002
@@ -99,6 +176,16 @@ type Image struct {
 	Image       *format.Image
 }
 
+func (img *Image) clone() *Image {
+	clone := *img
+	if img.Image != nil {
+		pix := *img.Image
+		pix.Pix = slices.Clone(pix.Pix)
+		clone.Image = &pix
+	}
+	return &clone
+}
+
 // BestSize searches the available sizes for the cursor and returns
 // the one that is closest to the target size. If two are equidistant
 // to size, the larger of the two is returned.
@@ -109,6 +196,33 @@ func (c *Cursor) BestSize(size int) (best int) {
 	return best
 }
 
+// BestImages returns the frames of the size returned by
+// [Cursor.BestSize]. If the cursor has no images, it returns an empty,
+// non-nil slice.
+func (c *Cursor) BestImages(size int) []*Image {
+	imgs := c.Images[c.BestSize(size)]
+	if imgs == nil {
+		return []*Image{}
+	}
+	return imgs
+}
+
+// BestImagesErr is like [Cursor.BestImages] but returns ErrNoImages if
+// the cursor has no images.
+func (c *Cursor) BestImagesErr(size int) ([]*Image, error) {
+	imgs := c.BestImages(size)
+	if len(imgs) == 0 {
+		return nil, ErrNoImages
+	}
+	return imgs, nil
+}
+
+// BestSizeForDPI is like [Cursor.BestSize] but calculates the target
+// size by scaling nominalPx from the standard 96 DPI to dpi.
+func (c *Cursor) BestSizeForDPI(nominalPx int, dpi int) int {
+	return c.BestSize(nominalPx * dpi / 96)
+}
+
 func betterSize(target, a, b int) int {
 	da := dist(target, a)
 	db := dist(target, b)
@@ -159,6 +273,73 @@ func Decode(r io.Reader) (*Cursor, error) {
 	return d.Decode()
 }
 
+// CursorHeader contains the metadata of an Xcursor file without any
+// of its pixel data.
+type CursorHeader struct {
+	Comments []*Comment
+
+	// Sizes is the list of nominal sizes available in the file in
+	// ascending order.
+	Sizes []int
+
+	// FramesPerSize maps each nominal size to its number of frames.
+	FramesPerSize map[int]int
+
+	// Delays maps each nominal size to the delays of each of its
+	// frames in the order that they appear in the file.
+	Delays map[int][]time.Duration
+}
+
+// DecodeHeaderFile is like [DecodeHeader] but reads from the file at
+// path.
+func DecodeHeaderFile(path string) (*CursorHeader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open: %w", err)
+	}
+	defer file.Close()
+
+	return DecodeHeader(file)
+}
+
+// DecodeHeader decodes the table of contents and chunk headers of an
+// Xcursor file from r. Pixel data is skipped instead of being read
+// into memory, so this is much cheaper than [Decode] when only
+// information about the available sizes and frames is needed.
+func DecodeHeader(r io.Reader) (*CursorHeader, error) {
+	d := decoder{
+		r:  r,
+		br: bufio.NewReader(r),
+	}
+	return d.DecodeHeader()
+}
+
+// CheckVersion reads just the header of an Xcursor file from r and
+// returns the major version of the file format that it specifies,
+// which is stored in the upper 16 bits of the header's version field.
+// If the version is not one that is supported by this package, the
+// version is returned along with ErrUnsupportedVersion.
+func CheckVersion(r io.Reader) (uint32, error) {
+	var header [12]byte
+	_, err := io.ReadFull(r, header[:])
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return 0, err
+	}
+
+	if binary.LittleEndian.Uint32(header[:]) != fileMagic {
+		return 0, ErrBadMagic
+	}
+
+	version := binary.LittleEndian.Uint32(header[8:]) >> 16
+	if version != fileVersion>>16 {
+		return version, ErrUnsupportedVersion
+	}
+	return version, nil
+}
+
 func (d *decoder) Decode() (c *Cursor, err error) {
 	if d.err != nil {
 		return nil, d.err
@@ -188,6 +369,41 @@ func (d *decoder) Decode() (c *Cursor, err error) {
 	return &cursor, nil
 }
 
+func (d *decoder) DecodeHeader() (h *CursorHeader, err error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	defer d.catch(&err)
+
+	header := CursorHeader{
+		FramesPerSize: make(map[int]int),
+		Delays:        make(map[int][]time.Duration),
+	}
+
+	tocs := d.header()
+	for _, toc := range tocs {
+		d.SeekTo(int(toc.Position))
+		d.tocHeader(toc)
+		switch toc.Type {
+		case tocTypeComment:
+			header.Comments = append(header.Comments, d.comment(toc))
+		case tocTypeImage:
+			img, _, _ := d.imageHeader(toc)
+			if header.FramesPerSize[img.NominalSize] == 0 {
+				header.Sizes = append(header.Sizes, img.NominalSize)
+			}
+			header.FramesPerSize[img.NominalSize]++
+			header.Delays[img.NominalSize] = append(header.Delays[img.NominalSize], img.Delay)
+		default:
+			d.throw(fmt.Errorf("unknown TOC type: %x", toc.Type))
+		}
+	}
+
+	slices.Sort(header.Sizes)
+	return &header, nil
+}
+
 func (d *decoder) header() []fileToc {
 	magic := d.uint32()
 	if magic != fileMagic {
@@ -240,26 +456,32 @@ func (d *decoder) comment(toc fileToc) *Comment {
 }
 
 func (d *decoder) image(toc fileToc) *Image {
-	w := d.uint32()
-	h := d.uint32()
-	xhot := d.uint32()
-	yhot := d.uint32()
-	delay := d.uint32()
+	img, w, h := d.imageHeader(toc)
 
 	pixels := make([]byte, w*h*4)
 	_, err := io.ReadFull(d, pixels)
 	d.throw(err)
 
+	img.Image = &format.Image{
+		Format: format.ARGB8888,
+		Rect:   image.Rect(0, 0, int(w), int(h)),
+		Pix:    pixels,
+	}
+	return img
+}
+
+func (d *decoder) imageHeader(toc fileToc) (img *Image, w, h uint32) {
+	w = d.uint32()
+	h = d.uint32()
+	xhot := d.uint32()
+	yhot := d.uint32()
+	delay := d.uint32()
+
 	return &Image{
 		NominalSize: int(toc.Subtype),
 		Delay:       time.Duration(delay) * time.Millisecond,
 		Hot:         image.Pt(int(xhot), int(yhot)),
-		Image: &format.Image{
-			Format: format.ARGB8888,
-			Rect:   image.Rect(0, 0, int(w), int(h)),
-			Pix:    pixels,
-		},
-	}
+	}, w, h
 }
 
 func (d *decoder) uint32() (v uint32) {
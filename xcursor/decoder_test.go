@@ -1,10 +1,18 @@
 package xcursor_test
 
 import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
 	"image"
+	"image/color"
 	_ "image/png"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"deedles.dev/ximage/xcursor"
 	"github.com/stretchr/testify/require"
@@ -38,3 +46,180 @@ func TestDecode(t *testing.T) {
 		}
 	}
 }
+
+func TestCheckVersion(t *testing.T) {
+	data, err := os.ReadFile("testdata/left_ptr")
+	require.Nil(t, err)
+
+	version, err := xcursor.CheckVersion(bytes.NewReader(data))
+	require.Nil(t, err)
+	require.Equal(t, uint32(1), version)
+
+	future := bytes.Clone(data)
+	binary.LittleEndian.PutUint32(future[8:], 0x20000)
+	version, err = xcursor.CheckVersion(bytes.NewReader(future))
+	require.Equal(t, xcursor.ErrUnsupportedVersion, err)
+	require.Equal(t, uint32(2), version)
+
+	version, err = xcursor.CheckVersion(strings.NewReader("not a cursor"))
+	require.Equal(t, xcursor.ErrBadMagic, err)
+	require.Zero(t, version)
+
+	version, err = xcursor.CheckVersion(bytes.NewReader(data[:6]))
+	require.Equal(t, io.ErrUnexpectedEOF, err)
+	require.Zero(t, version)
+}
+
+func TestBestSizeForDPI(t *testing.T) {
+	c := xcursor.Cursor{
+		Images: map[int][]*xcursor.Image{24: nil, 32: nil, 48: nil, 64: nil},
+	}
+	require.Equal(t, 48, c.BestSizeForDPI(24, 192))
+	require.Equal(t, 24, c.BestSizeForDPI(24, 96))
+	require.Equal(t, 32, c.BestSizeForDPI(24, 120))
+}
+
+func TestBestImages(t *testing.T) {
+	c, err := xcursor.DecodeFile("testdata/left_ptr")
+	require.Nil(t, err)
+
+	imgs := c.BestImages(24)
+	require.Equal(t, c.Images[16], imgs)
+	imgs, err = c.BestImagesErr(24)
+	require.Nil(t, err)
+	require.Equal(t, c.Images[16], imgs)
+
+	var empty xcursor.Cursor
+	imgs = empty.BestImages(24)
+	require.Empty(t, imgs)
+	require.NotNil(t, imgs)
+	imgs, err = empty.BestImagesErr(24)
+	require.Equal(t, xcursor.ErrNoImages, err)
+	require.Nil(t, imgs)
+}
+
+func TestAddComment(t *testing.T) {
+	var c xcursor.Cursor
+	c.AddCopyrightComment("(c) Somebody")
+	c.AddLicenseComment("MIT")
+	require.Nil(t, c.AddComment(xcursor.CommentSubtypeOther, "other"))
+	require.NotNil(t, c.AddComment(xcursor.CommentSubtype(17), "invalid"))
+
+	require.Equal(t, []*xcursor.Comment{
+		{Subtype: xcursor.CommentSubtypeCopyright, Comment: "(c) Somebody"},
+		{Subtype: xcursor.CommentSubtypeLicense, Comment: "MIT"},
+		{Subtype: xcursor.CommentSubtypeOther, Comment: "other"},
+	}, c.Comments)
+
+	var buf bytes.Buffer
+	require.Nil(t, xcursor.Encode(&buf, &c))
+	decoded, err := xcursor.Decode(&buf)
+	require.Nil(t, err)
+	require.Equal(t, c.Comments, decoded.Comments)
+}
+
+func TestCursorClone(t *testing.T) {
+	c, err := xcursor.DecodeFile("testdata/left_ptr")
+	require.Nil(t, err)
+	c.AddLicenseComment("MIT")
+
+	clone := c.Clone()
+	require.Equal(t, c, clone)
+	require.NotSame(t, c.Comments[0], clone.Comments[0])
+	require.NotSame(t, c.Images[16][0], clone.Images[16][0])
+	require.NotSame(t, c.Images[16][0].Image, clone.Images[16][0].Image)
+
+	clone.Comments[0].Comment = "GPL"
+	clone.Images[16][0].Image.Pix[0] ^= 0xFF
+	clone.Images[32] = clone.Images[16]
+	require.Equal(t, "MIT", c.Comments[0].Comment)
+	require.NotEqual(t, c.Images[16][0].Image.Pix[0], clone.Images[16][0].Image.Pix[0])
+	require.NotContains(t, c.Images, 32)
+}
+
+func TestWritePNG(t *testing.T) {
+	c, err := xcursor.DecodeFile("testdata/left_ptr")
+	require.Nil(t, err)
+
+	dir := t.TempDir()
+	require.Nil(t, c.WritePNG(dir))
+
+	expected := decodePNG(t, "testdata/left_ptr.png")
+	written := decodePNG(t, filepath.Join(dir, fmt.Sprintf("%v_0.png", c.BestSize(24))))
+	bounds := expected.Bounds()
+	require.Equal(t, bounds, written.Bounds())
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			require.Equal(t, color.NRGBAModel.Convert(expected.At(x, y)), written.At(x, y))
+		}
+	}
+}
+
+func decodePNG(t *testing.T, path string) image.Image {
+	file, err := os.Open(path)
+	require.Nil(t, err)
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	require.Nil(t, err)
+	return img
+}
+
+func TestDecodeHeader(t *testing.T) {
+	c, err := xcursor.DecodeFile("testdata/left_ptr")
+	require.Nil(t, err)
+
+	header, err := xcursor.DecodeHeaderFile("testdata/left_ptr")
+	require.Nil(t, err)
+	require.Equal(t, []int{16}, header.Sizes)
+	require.Equal(t, map[int]int{16: 1}, header.FramesPerSize)
+	require.Equal(t, map[int][]time.Duration{16: {c.Images[16][0].Delay}}, header.Delays)
+
+	c.AddLicenseComment("MIT")
+	c.Images[32] = []*xcursor.Image{
+		{NominalSize: 32, Delay: 10 * time.Millisecond, Image: c.Images[16][0].Image},
+		{NominalSize: 32, Delay: 20 * time.Millisecond, Image: c.Images[16][0].Image},
+	}
+
+	var buf bytes.Buffer
+	require.Nil(t, xcursor.Encode(&buf, c))
+	header, err = xcursor.DecodeHeader(&buf)
+	require.Nil(t, err)
+	require.Equal(t, c.Comments, header.Comments)
+	require.Equal(t, []int{16, 32}, header.Sizes)
+	require.Equal(t, map[int]int{16: 1, 32: 2}, header.FramesPerSize)
+	require.Equal(t, []time.Duration{10 * time.Millisecond, 20 * time.Millisecond}, header.Delays[32])
+}
+
+func TestEachImage(t *testing.T) {
+	c := xcursor.Cursor{
+		Images: map[int][]*xcursor.Image{
+			48: {{NominalSize: 48}},
+			24: {{NominalSize: 24, Delay: 1}, {NominalSize: 24, Delay: 2}},
+			32: {{NominalSize: 32}},
+		},
+	}
+
+	var sizes []int
+	var imgs []*xcursor.Image
+	for size, img := range c.EachImage() {
+		sizes = append(sizes, size)
+		imgs = append(imgs, img)
+	}
+	require.Equal(t, []int{24, 24, 32, 48}, sizes)
+	require.Len(t, imgs, 4)
+	require.Same(t, c.Images[24][0], imgs[0])
+	require.Same(t, c.Images[24][1], imgs[1])
+	require.Same(t, c.Images[32][0], imgs[2])
+	require.Same(t, c.Images[48][0], imgs[3])
+
+	single, err := xcursor.DecodeFile("testdata/left_ptr")
+	require.Nil(t, err)
+	var n int
+	for size, img := range single.EachImage() {
+		require.Equal(t, 16, size)
+		require.Same(t, single.Images[16][0], img)
+		n++
+	}
+	require.Equal(t, 1, n)
+}
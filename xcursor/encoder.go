@@ -0,0 +1,145 @@
+package xcursor
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"deedles.dev/ximage/format"
+)
+
+const (
+	fileHeaderSize    = 16
+	tocEntrySize      = 12
+	commentHeaderSize = 20
+	imageHeaderSize   = 36
+	chunkVersion      = 1
+)
+
+// Encode encodes c as an Xcursor file and writes it to w. Comments are
+// written first, followed by every frame of every size in order of
+// ascending nominal size. Images that are not in the ARGB8888 format
+// are converted.
+func Encode(w io.Writer, c *Cursor) error {
+	type chunk struct {
+		toc        fileToc
+		headerSize uint32
+		size       uint32
+		write      func(w io.Writer)
+	}
+
+	var chunks []chunk
+	for _, comment := range c.Comments {
+		chunks = append(chunks, chunk{
+			toc: fileToc{
+				Type:    tocTypeComment,
+				Subtype: uint32(comment.Subtype),
+			},
+			headerSize: commentHeaderSize,
+			size:       commentHeaderSize + uint32(len(comment.Comment)),
+			write: func(w io.Writer) {
+				binary.Write(w, binary.LittleEndian, uint32(len(comment.Comment)))
+				io.WriteString(w, comment.Comment)
+			},
+		})
+	}
+	for size, img := range c.EachImage() {
+		pix := img.Image
+		if pix.Format != format.ARGB8888 {
+			pix = format.NewImage(format.ARGB8888, img.Image.Rect)
+			format.ConvertImage(pix, img.Image)
+		}
+
+		chunks = append(chunks, chunk{
+			toc: fileToc{
+				Type:    tocTypeImage,
+				Subtype: uint32(size),
+			},
+			headerSize: imageHeaderSize,
+			size:       imageHeaderSize + uint32(4*pix.Rect.Dx()*pix.Rect.Dy()),
+			write: func(w io.Writer) {
+				binary.Write(w, binary.LittleEndian, [...]uint32{
+					uint32(pix.Rect.Dx()),
+					uint32(pix.Rect.Dy()),
+					uint32(img.Hot.X),
+					uint32(img.Hot.Y),
+					uint32(img.Delay.Milliseconds()),
+				})
+				pix.WriteTo(w)
+			},
+		})
+	}
+
+	pos := uint32(fileHeaderSize + tocEntrySize*len(chunks))
+	for i := range chunks {
+		chunks[i].toc.Position = pos
+		pos += chunks[i].size
+	}
+
+	bw := bufio.NewWriter(w)
+	binary.Write(bw, binary.LittleEndian, [...]uint32{
+		fileMagic,
+		fileHeaderSize,
+		fileVersion,
+		uint32(len(chunks)),
+	})
+	for _, chunk := range chunks {
+		binary.Write(bw, binary.LittleEndian, chunk.toc)
+	}
+	for _, chunk := range chunks {
+		binary.Write(bw, binary.LittleEndian, [...]uint32{
+			chunk.headerSize,
+			chunk.toc.Type,
+			chunk.toc.Subtype,
+			chunkVersion,
+		})
+		chunk.write(bw)
+	}
+
+	err := bw.Flush()
+	if err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+	return nil
+}
+
+// EncodeFile encodes c as an Xcursor file and writes it to path. The
+// data is first written to a temporary file in the same directory
+// which is then renamed over path, so a failed write never leaves a
+// partial file behind.
+func EncodeFile(path string, c *Cursor) (err error) {
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("create temporary file: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			file.Close()
+			os.Remove(file.Name())
+		}
+	}()
+
+	err = Encode(file, c)
+	if err != nil {
+		return err
+	}
+
+	err = file.Chmod(0644)
+	if err != nil {
+		return fmt.Errorf("chmod: %w", err)
+	}
+
+	err = file.Close()
+	if err != nil {
+		return fmt.Errorf("close: %w", err)
+	}
+
+	err = os.Rename(file.Name(), path)
+	if err != nil {
+		return fmt.Errorf("rename: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package xcursor_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"deedles.dev/ximage/format"
+	"deedles.dev/ximage/xcursor"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncode(t *testing.T) {
+	data, err := os.ReadFile("testdata/left_ptr")
+	require.Nil(t, err)
+
+	c, err := xcursor.Decode(bytes.NewReader(data))
+	require.Nil(t, err)
+
+	var buf bytes.Buffer
+	require.Nil(t, xcursor.Encode(&buf, c))
+	require.Equal(t, data, buf.Bytes())
+}
+
+func TestEncodeFile(t *testing.T) {
+	c, err := xcursor.DecodeFile("testdata/left_ptr")
+	require.Nil(t, err)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "left_ptr")
+	require.Nil(t, xcursor.EncodeFile(path, c))
+
+	info, err := os.Stat(path)
+	require.Nil(t, err)
+	require.Equal(t, os.FileMode(0644), info.Mode().Perm())
+
+	entries, err := os.ReadDir(dir)
+	require.Nil(t, err)
+	require.Len(t, entries, 1)
+
+	decoded, err := xcursor.DecodeFile(path)
+	require.Nil(t, err)
+	require.Equal(t, c, decoded)
+}
+
+func TestEncodeMultipleSizes(t *testing.T) {
+	c := xcursor.Cursor{Images: make(map[int][]*xcursor.Image)}
+	for _, size := range []int{64, 24, 48, 32} {
+		for frame := range 2 {
+			img := format.NewImage(format.ARGB8888, image.Rect(0, 0, size, size))
+			img.Fill(img.Rect, color.NRGBA{R: uint8(size), G: uint8(frame), A: 255})
+			c.Images[size] = append(c.Images[size], &xcursor.Image{
+				NominalSize: size,
+				Delay:       time.Duration(frame+1) * 50 * time.Millisecond,
+				Hot:         image.Pt(size/2, frame),
+				Image:       img,
+			})
+		}
+	}
+
+	var buf bytes.Buffer
+	require.Nil(t, xcursor.Encode(&buf, &c))
+	decoded, err := xcursor.Decode(&buf)
+	require.Nil(t, err)
+	require.Equal(t, c.Images, decoded.Images)
+
+	tests := []struct {
+		target, best int
+	}{
+		{16, 24},
+		{24, 24},
+		{28, 32},
+		{40, 48},
+		{56, 64},
+		{96, 64},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.best, decoded.BestSize(test.target), "target: %v", test.target)
+	}
+}
@@ -0,0 +1,126 @@
+package xcursor
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"io"
+	"math"
+	"sync"
+	"time"
+
+	"deedles.dev/ximage/format"
+)
+
+// LazyCursor is like [Cursor] but its images do not read their pixel
+// data until it is requested.
+type LazyCursor struct {
+	Comments []*Comment
+	Images   map[int][]*LazyImage
+}
+
+// LazyImage is like [Image] but defers reading its pixel data until
+// the first call to its Image method. The pixel data is cached after
+// it has been read. It is safe to call Image concurrently.
+type LazyImage struct {
+	NominalSize int
+	Delay       time.Duration
+	Hot         image.Point
+
+	r      io.ReaderAt
+	offset int64
+	w, h   int
+
+	once sync.Once
+	img  *format.Image
+	err  error
+}
+
+// DecodeLazy decodes an Xcursor file from r, reading everything but
+// the pixel data of the images. The pixel data is read from r later
+// when it is requested, so r must remain valid for as long as the
+// returned cursor's images are in use.
+func DecodeLazy(r io.ReaderAt) (*LazyCursor, error) {
+	sr := io.NewSectionReader(r, 0, math.MaxInt64)
+	d := decoder{
+		r:  sr,
+		br: bufio.NewReader(sr),
+	}
+	return d.DecodeLazy(r)
+}
+
+func (d *decoder) DecodeLazy(r io.ReaderAt) (c *LazyCursor, err error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	defer d.catch(&err)
+
+	cursor := LazyCursor{
+		Images: make(map[int][]*LazyImage),
+	}
+
+	tocs := d.header()
+	for _, toc := range tocs {
+		d.SeekTo(int(toc.Position))
+		d.tocHeader(toc)
+		switch toc.Type {
+		case tocTypeComment:
+			cursor.Comments = append(cursor.Comments, d.comment(toc))
+		case tocTypeImage:
+			img, w, h := d.imageHeader(toc)
+			cursor.Images[img.NominalSize] = append(cursor.Images[img.NominalSize], &LazyImage{
+				NominalSize: img.NominalSize,
+				Delay:       img.Delay,
+				Hot:         img.Hot,
+				r:           r,
+				offset:      int64(d.n),
+				w:           int(w),
+				h:           int(h),
+			})
+		default:
+			d.throw(fmt.Errorf("unknown TOC type: %x", toc.Type))
+		}
+	}
+
+	return &cursor, nil
+}
+
+// Image returns the pixel data of the image, reading it if it has not
+// yet been read.
+func (img *LazyImage) Image() (*format.Image, error) {
+	img.once.Do(func() {
+		pixels := make([]byte, img.w*img.h*4)
+		n, err := img.r.ReadAt(pixels, img.offset)
+		if n < len(pixels) {
+			if err == nil || err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			img.err = fmt.Errorf("read pixels: %w", err)
+			return
+		}
+
+		img.img = &format.Image{
+			Format: format.ARGB8888,
+			Rect:   image.Rect(0, 0, img.w, img.h),
+			Pix:    pixels,
+		}
+	})
+	return img.img, img.err
+}
+
+// Load reads the pixel data of the image and returns it as a regular
+// [Image].
+func (img *LazyImage) Load() (*Image, error) {
+	pix, err := img.Image()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Image{
+		NominalSize: img.NominalSize,
+		Delay:       img.Delay,
+		Hot:         img.Hot,
+		Image:       pix,
+	}, nil
+}
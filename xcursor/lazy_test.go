@@ -0,0 +1,56 @@
+package xcursor_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"sync"
+	"testing"
+
+	"deedles.dev/ximage/xcursor"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeLazy(t *testing.T) {
+	data, err := os.ReadFile("testdata/left_ptr")
+	require.Nil(t, err)
+
+	c, err := xcursor.Decode(bytes.NewReader(data))
+	require.Nil(t, err)
+
+	lazy, err := xcursor.DecodeLazy(bytes.NewReader(data))
+	require.Nil(t, err)
+	require.Equal(t, c.Comments, lazy.Comments)
+	require.Len(t, lazy.Images[16], 1)
+
+	limg := lazy.Images[16][0]
+	require.Equal(t, c.Images[16][0].Hot, limg.Hot)
+	require.Equal(t, c.Images[16][0].Delay, limg.Delay)
+
+	var wg sync.WaitGroup
+	imgs := make([]*xcursor.Image, 4)
+	errs := make([]error, len(imgs))
+	for i := range imgs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			imgs[i], errs[i] = limg.Load()
+		}()
+	}
+	wg.Wait()
+	for i := range imgs {
+		require.Nil(t, errs[i])
+		require.Equal(t, c.Images[16][0], imgs[i])
+	}
+
+	first, err := limg.Image()
+	require.Nil(t, err)
+	second, err := limg.Image()
+	require.Nil(t, err)
+	require.Same(t, first, second)
+
+	lazy, err = xcursor.DecodeLazy(bytes.NewReader(data[:len(data)-1]))
+	require.Nil(t, err)
+	_, err = lazy.Images[16][0].Image()
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,51 @@
+package xcursor
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+)
+
+// WritePNG writes every image in the cursor to dir as a PNG file,
+// creating dir if necessary. Each file is named after the nominal size
+// and frame index of the image, such as "24_0.png". This is primarily
+// useful for debugging.
+func (c *Cursor) WritePNG(dir string) error {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+
+	for size, frames := range c.Images {
+		for i, frame := range frames {
+			path := filepath.Join(dir, fmt.Sprintf("%v_%v.png", size, i))
+			err := writePNG(path, frame.Image)
+			if err != nil {
+				return fmt.Errorf("write %q: %w", path, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func writePNG(path string, img image.Image) (err error) {
+	nrgba := image.NewNRGBA(img.Bounds())
+	draw.Draw(nrgba, nrgba.Rect, img, img.Bounds().Min, draw.Src)
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		cerr := file.Close()
+		if err == nil {
+			err = cerr
+		}
+	}()
+
+	return png.Encode(file, nrgba)
+}
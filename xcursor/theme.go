@@ -2,12 +2,19 @@ package xcursor
 
 import (
 	"bufio"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"iter"
+	"log/slog"
+	"maps"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"deedles.dev/xiter"
@@ -44,10 +51,62 @@ func libraryPaths() iter.Seq[string] {
 	}
 }
 
+// Aliases maps cursor names to alternative names that
+// [Theme.BestCursor] tries, in order, when a theme has no cursor by
+// the original name. It covers the standard CSS cursor names and the
+// legacy X11 names that they correspond to. Entries may be added to
+// it to support other names.
+var Aliases = map[string][]string{
+	"default":     {"left_ptr", "arrow"},
+	"left_ptr":    {"default", "arrow"},
+	"pointer":     {"hand2", "hand1", "hand"},
+	"hand2":       {"pointer", "hand1", "hand"},
+	"text":        {"xterm", "ibeam"},
+	"xterm":       {"text", "ibeam"},
+	"wait":        {"watch"},
+	"watch":       {"wait"},
+	"progress":    {"left_ptr_watch", "half-busy"},
+	"crosshair":   {"cross", "tcross"},
+	"cross":       {"crosshair", "tcross"},
+	"help":        {"question_arrow", "whats_this"},
+	"move":        {"fleur", "all-scroll"},
+	"fleur":       {"move", "all-scroll"},
+	"not-allowed": {"crossed_circle", "forbidden"},
+	"grab":        {"openhand"},
+	"grabbing":    {"closedhand"},
+	"n-resize":    {"top_side"},
+	"s-resize":    {"bottom_side"},
+	"e-resize":    {"right_side"},
+	"w-resize":    {"left_side"},
+	"ne-resize":   {"top_right_corner"},
+	"nw-resize":   {"top_left_corner"},
+	"se-resize":   {"bottom_right_corner"},
+	"sw-resize":   {"bottom_left_corner"},
+	"ns-resize":   {"sb_v_double_arrow", "v_double_arrow"},
+	"ew-resize":   {"sb_h_double_arrow", "h_double_arrow"},
+}
+
 // Theme is an Xcursor theme.
 type Theme struct {
 	Name    string
 	Cursors map[string]*Cursor
+
+	// Overrides maps cursor names to the paths of the files that were
+	// loaded in place of the theme's own cursors via [Theme.Override].
+	Overrides map[string]string
+
+	// dir is the directory that the theme was loaded from if it was
+	// loaded via LoadThemeFromDir.
+	dir string
+
+	// fsys is the filesystem that the theme was loaded from if it was
+	// loaded via LoadThemeFromFS.
+	fsys fs.FS
+}
+
+type themeJSON struct {
+	Name      string            `json:"name"`
+	Overrides map[string]string `json:"overrides,omitempty"`
 }
 
 // LoadTheme loads the named theme from the system search paths. It
@@ -74,10 +133,284 @@ func LoadThemeFromDir(path string) (*Theme, error) {
 	c := Theme{
 		Name:    filepath.Base(path),
 		Cursors: make(map[string]*Cursor),
+		dir:     path,
 	}
 	return &c, c.loadDir(path)
 }
 
+// LoadThemeFromFS loads the named theme from fsys. The theme's cursors
+// are expected to be in the directory name/cursors. If name/index.theme
+// exists and lists other themes to inherit from, those themes are also
+// loaded from fsys and their cursors are added to the returned theme.
+func LoadThemeFromFS(fsys fs.FS, name string) (*Theme, error) {
+	c := Theme{
+		Name:    name,
+		Cursors: make(map[string]*Cursor),
+		fsys:    fsys,
+	}
+	return &c, c.loadFS(fsys, name)
+}
+
+// ListThemes returns the names of all of the themes that can be
+// found in the system search paths, sorted and with duplicates
+// removed. A theme is any directory in a search path that contains a
+// cursors subdirectory. Search paths and theme directories that can
+// not be read are logged via [log/slog] and skipped. Search paths that
+// do not exist are logged at the debug level, as most systems will be
+// missing at least some of them.
+func ListThemes() ([]string, error) {
+	themes := make(map[string]struct{})
+	for path := range libraryPaths() {
+		err := listThemes(path, themes)
+		if err != nil {
+			level := slog.LevelWarn
+			if errors.Is(err, fs.ErrNotExist) {
+				level = slog.LevelDebug
+			}
+			slog.Log(context.Background(), level, "skipping cursor theme search path", "path", path, "err", err)
+		}
+	}
+	return slices.Sorted(maps.Keys(themes)), nil
+}
+
+// ListThemesFromPath is like [ListThemes] but only searches the
+// directory at path. Unlike ListThemes, it returns an error if path
+// itself can not be read. Theme directories inside of path that can
+// not be read are still logged and skipped.
+func ListThemesFromPath(path string) ([]string, error) {
+	themes := make(map[string]struct{})
+	err := listThemes(path, themes)
+	if err != nil {
+		return nil, err
+	}
+	return slices.Sorted(maps.Keys(themes)), nil
+}
+
+func listThemes(path string, themes map[string]struct{}) error {
+	dir, err := os.ReadDir(path)
+	if err != nil {
+		return fmt.Errorf("read dir: %w", err)
+	}
+
+	for _, ent := range dir {
+		if !ent.IsDir() && (ent.Type() != fs.ModeSymlink) {
+			continue
+		}
+
+		cursors := filepath.Join(path, ent.Name(), "cursors")
+		info, err := os.Stat(cursors)
+		if err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				slog.Warn("skipping unreadable cursor theme directory", "path", cursors, "err", err)
+			}
+			continue
+		}
+		if !info.IsDir() {
+			continue
+		}
+		themes[ent.Name()] = struct{}{}
+	}
+
+	return nil
+}
+
+// UnmarshalTheme loads a theme from the JSON representation produced
+// by [Theme.MarshalJSON]. The theme itself is loaded via [LoadTheme]
+// and then any overrides are applied to it.
+func UnmarshalTheme(data []byte) (*Theme, error) {
+	var tj themeJSON
+	err := json.Unmarshal(data, &tj)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal: %w", err)
+	}
+
+	t, err := LoadTheme(tj.Name)
+	if err != nil {
+		return nil, fmt.Errorf("load theme %q: %w", tj.Name, err)
+	}
+	for name, path := range tj.Overrides {
+		err := t.Override(name, path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return t, nil
+}
+
+// MarshalJSON implements json.Marshaler. Only the name of the theme
+// and its overrides are stored. Use [UnmarshalTheme] to load a theme
+// from the result.
+func (t *Theme) MarshalJSON() ([]byte, error) {
+	return json.Marshal(themeJSON{
+		Name:      t.Name,
+		Overrides: t.Overrides,
+	})
+}
+
+// Override decodes the Xcursor file at path and uses it in place of
+// the named cursor in the theme. The path is recorded in Overrides.
+func (t *Theme) Override(name, path string) error {
+	cur, err := DecodeFile(path)
+	if err != nil {
+		return fmt.Errorf("load override %q: %w", path, err)
+	}
+
+	if t.Overrides == nil {
+		t.Overrides = make(map[string]string)
+	}
+	t.Cursors[name] = cur
+	t.Overrides[name] = path
+	return nil
+}
+
+// Clone returns a copy of t in which every cursor has been cloned via
+// [Cursor.Clone].
+func (t *Theme) Clone() *Theme {
+	clone := *t
+	clone.Cursors = make(map[string]*Cursor, len(t.Cursors))
+	for name, c := range t.Cursors {
+		if c != nil {
+			c = c.Clone()
+		}
+		clone.Cursors[name] = c
+	}
+	clone.Overrides = maps.Clone(t.Overrides)
+	return &clone
+}
+
+// Merge adds all of the cursors from src to t. Cursors that t already
+// has are not replaced. The cursors themselves are not copied, so
+// both themes will share them afterwards.
+func (t *Theme) Merge(src *Theme) {
+	t.merge(src, false)
+}
+
+// MergeOverwrite is like [Theme.Merge] but replaces cursors that t
+// already has with the ones from src.
+func (t *Theme) MergeOverwrite(src *Theme) {
+	t.merge(src, true)
+}
+
+func (t *Theme) merge(src *Theme, overwrite bool) {
+	if t.Cursors == nil {
+		t.Cursors = make(map[string]*Cursor, len(src.Cursors))
+	}
+
+	for name, c := range src.Cursors {
+		if _, ok := t.Cursors[name]; ok && !overwrite {
+			continue
+		}
+		t.Cursors[name] = c
+	}
+}
+
+// Cursor returns the named cursor from the theme. If the theme has no
+// such cursor, or if the cursor is nil, it returns nil and false.
+func (t *Theme) Cursor(name string) (*Cursor, bool) {
+	c := t.Cursors[name]
+	return c, c != nil
+}
+
+// Names returns the names of all of the cursors in the theme, sorted.
+// If the theme has no cursors, the returned slice is empty but not
+// nil.
+func (t *Theme) Names() []string {
+	names := slices.AppendSeq(make([]string, 0, len(t.Cursors)), maps.Keys(t.Cursors))
+	slices.Sort(names)
+	return names
+}
+
+// BestCursor looks up the named cursor in the theme, falling back to
+// the names listed for it in [Aliases] if the theme has no cursor by
+// that name. It returns the first frame of the best available size
+// as determined by [Cursor.BestSize].
+func (t *Theme) BestCursor(name string, size int) (*Image, error) {
+	c, ok := t.Cursor(name)
+	if !ok {
+		for _, alias := range Aliases[name] {
+			c, ok = t.Cursor(alias)
+			if ok {
+				break
+			}
+		}
+	}
+	if !ok {
+		return nil, fmt.Errorf("theme %q has no cursor named %q or any of its aliases", t.Name, name)
+	}
+
+	imgs := c.Images[c.BestSize(size)]
+	if len(imgs) == 0 {
+		return nil, fmt.Errorf("cursor %q in theme %q has no images", name, t.Name)
+	}
+	return imgs[0], nil
+}
+
+// EachCursor returns an iterator over the cursors in the theme and
+// their names, sorted by name.
+func (t *Theme) EachCursor() iter.Seq2[string, *Cursor] {
+	return func(yield func(string, *Cursor) bool) {
+		for _, name := range t.Names() {
+			if !yield(name, t.Cursors[name]) {
+				return
+			}
+		}
+	}
+}
+
+// ListCursorNames returns the names of all of the cursors in the
+// theme that match pattern, sorted. Matching uses the semantics of
+// [path.Match]. An error is returned only if pattern is malformed.
+func (t *Theme) ListCursorNames(pattern string) ([]string, error) {
+	names := []string{}
+	for name := range t.Cursors {
+		ok, err := path.Match(pattern, name)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// Reload reloads all of the cursors in the theme from the same
+// location that they were originally loaded from, reapplying any
+// overrides afterwards. If an error occurs, t is left unmodified.
+func (t *Theme) Reload() error {
+	tmp := Theme{
+		Name:    t.Name,
+		Cursors: make(map[string]*Cursor),
+		dir:     t.dir,
+		fsys:    t.fsys,
+	}
+
+	var err error
+	switch {
+	case t.fsys != nil:
+		err = tmp.loadFS(t.fsys, t.Name)
+	case t.dir != "":
+		err = tmp.loadDir(t.dir)
+	default:
+		err = tmp.load(t.Name)
+	}
+	if err != nil {
+		return err
+	}
+
+	for name, path := range t.Overrides {
+		err := tmp.Override(name, path)
+		if err != nil {
+			return err
+		}
+	}
+
+	t.Cursors = tmp.Cursors
+	return nil
+}
+
 func (t *Theme) load(theme string) error {
 	for path := range libraryPaths() {
 		inherits, err := loadInherits(filepath.Join(path, theme, "index.theme"))
@@ -87,10 +420,12 @@ func (t *Theme) load(theme string) error {
 			}
 			return fmt.Errorf("load inherited themes: %w", err)
 		}
-		for theme := range inherits {
-			err := t.load(theme)
-			if err != nil {
-				return fmt.Errorf("load inherited theme %q: %w", theme, err)
+		if inherits != nil {
+			for theme := range inherits {
+				err := t.load(theme)
+				if err != nil {
+					return fmt.Errorf("load inherited theme %q: %w", theme, err)
+				}
 			}
 		}
 
@@ -135,6 +470,68 @@ func (t *Theme) loadDir(path string) error {
 	return nil
 }
 
+func (t *Theme) loadFS(fsys fs.FS, theme string) error {
+	inherits, err := loadInheritsFS(fsys, path.Join(theme, "index.theme"))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("load inherited themes: %w", err)
+	}
+	if inherits != nil {
+		for theme := range inherits {
+			err := t.loadFS(fsys, theme)
+			if err != nil {
+				return fmt.Errorf("load inherited theme %q: %w", theme, err)
+			}
+		}
+	}
+
+	dir := path.Join(theme, "cursors")
+	err = t.loadFSDir(fsys, dir)
+	if (err != nil) && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("load dir %q: %w", dir, err)
+	}
+
+	return nil
+}
+
+func (t *Theme) loadFSDir(fsys fs.FS, dir string) error {
+	ents, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return fmt.Errorf("read dir: %w", err)
+	}
+
+	for _, ent := range ents {
+		if _, ok := t.Cursors[ent.Name()]; ok {
+			continue
+		}
+		if t := ent.Type().Type(); !t.IsRegular() && (t != fs.ModeSymlink) {
+			continue
+		}
+
+		entpath := path.Join(dir, ent.Name())
+		cur, err := decodeFS(fsys, entpath)
+		if err != nil {
+			if errors.Is(err, ErrBadMagic) {
+				continue
+			}
+			return fmt.Errorf("load %q: %w", entpath, err)
+		}
+
+		t.Cursors[ent.Name()] = cur
+	}
+
+	return nil
+}
+
+func decodeFS(fsys fs.FS, name string) (*Cursor, error) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("open: %w", err)
+	}
+	defer file.Close()
+
+	return Decode(file)
+}
+
 func loadInherits(index string) (inherits iter.Seq[string], err error) {
 	file, err := os.Open(index)
 	if err != nil {
@@ -142,7 +539,21 @@ func loadInherits(index string) (inherits iter.Seq[string], err error) {
 	}
 	defer file.Close()
 
-	s := bufio.NewScanner(file)
+	return parseInherits(file)
+}
+
+func loadInheritsFS(fsys fs.FS, index string) (inherits iter.Seq[string], err error) {
+	file, err := fsys.Open(index)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return parseInherits(file)
+}
+
+func parseInherits(r io.Reader) (inherits iter.Seq[string], err error) {
+	s := bufio.NewScanner(r)
 	for s.Scan() {
 		line := s.Text()
 		if !strings.HasPrefix(line, "Inherits") {
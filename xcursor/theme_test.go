@@ -0,0 +1,362 @@
+package xcursor_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"deedles.dev/ximage/xcursor"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThemeJSON(t *testing.T) {
+	t.Setenv("XCURSOR_PATH", t.TempDir())
+
+	theme, err := xcursor.LoadTheme("test")
+	require.Nil(t, err)
+	require.Nil(t, theme.Override("left_ptr", "testdata/left_ptr"))
+
+	data, err := json.Marshal(theme)
+	require.Nil(t, err)
+
+	loaded, err := xcursor.UnmarshalTheme(data)
+	require.Nil(t, err)
+	require.Equal(t, theme.Name, loaded.Name)
+	require.Len(t, loaded.Cursors, len(theme.Cursors))
+	require.Equal(t, theme.Overrides, loaded.Overrides)
+}
+
+func TestListCursorNames(t *testing.T) {
+	theme := xcursor.Theme{
+		Cursors: map[string]*xcursor.Cursor{
+			"left_ptr":  nil,
+			"left_side": nil,
+			"hand":      nil,
+		},
+	}
+
+	names, err := theme.ListCursorNames("*")
+	require.Nil(t, err)
+	require.Equal(t, []string{"hand", "left_ptr", "left_side"}, names)
+
+	names, err = theme.ListCursorNames("left_*")
+	require.Nil(t, err)
+	require.Equal(t, []string{"left_ptr", "left_side"}, names)
+
+	names, err = theme.ListCursorNames("hand")
+	require.Nil(t, err)
+	require.Equal(t, []string{"hand"}, names)
+
+	names, err = theme.ListCursorNames("right_*")
+	require.Nil(t, err)
+	require.Empty(t, names)
+	require.NotNil(t, names)
+}
+
+func TestThemeNames(t *testing.T) {
+	theme := xcursor.Theme{
+		Cursors: map[string]*xcursor.Cursor{
+			"left_ptr": nil,
+			"hand":     nil,
+			"default":  nil,
+		},
+	}
+	require.Equal(t, []string{"default", "hand", "left_ptr"}, theme.Names())
+
+	var empty xcursor.Theme
+	names := empty.Names()
+	require.Empty(t, names)
+	require.NotNil(t, names)
+}
+
+func TestEachCursor(t *testing.T) {
+	a, b, c := new(xcursor.Cursor), new(xcursor.Cursor), new(xcursor.Cursor)
+	theme := xcursor.Theme{
+		Cursors: map[string]*xcursor.Cursor{"left_ptr": a, "hand": b, "default": c},
+	}
+
+	var names []string
+	var cursors []*xcursor.Cursor
+	for name, c := range theme.EachCursor() {
+		names = append(names, name)
+		cursors = append(cursors, c)
+	}
+	require.Equal(t, []string{"default", "hand", "left_ptr"}, names)
+	require.Len(t, cursors, 3)
+	require.Same(t, c, cursors[0])
+	require.Same(t, b, cursors[1])
+	require.Same(t, a, cursors[2])
+
+	for name := range theme.EachCursor() {
+		require.Equal(t, "default", name)
+		break
+	}
+}
+
+func TestThemeMerge(t *testing.T) {
+	a, b, c := new(xcursor.Cursor), new(xcursor.Cursor), new(xcursor.Cursor)
+	src := xcursor.Theme{
+		Cursors: map[string]*xcursor.Cursor{"left_ptr": a, "hand": b},
+	}
+
+	dst := xcursor.Theme{
+		Cursors: map[string]*xcursor.Cursor{"left_ptr": c},
+	}
+	dst.Merge(&src)
+	require.Len(t, dst.Cursors, 2)
+	require.Same(t, c, dst.Cursors["left_ptr"])
+	require.Same(t, b, dst.Cursors["hand"])
+
+	dst = xcursor.Theme{
+		Cursors: map[string]*xcursor.Cursor{"left_ptr": c},
+	}
+	dst.MergeOverwrite(&src)
+	require.Len(t, dst.Cursors, 2)
+	require.Same(t, a, dst.Cursors["left_ptr"])
+	require.Same(t, b, dst.Cursors["hand"])
+
+	var empty xcursor.Theme
+	empty.Merge(&src)
+	require.Equal(t, src.Cursors, empty.Cursors)
+}
+
+func TestThemeClone(t *testing.T) {
+	theme, err := xcursor.LoadThemeFromDir("testdata")
+	require.Nil(t, err)
+
+	clone := theme.Clone()
+	require.Equal(t, theme.Cursors, clone.Cursors)
+	require.NotSame(t, theme.Cursors["left_ptr"], clone.Cursors["left_ptr"])
+
+	delete(clone.Cursors, "left_ptr")
+	require.Contains(t, theme.Cursors, "left_ptr")
+}
+
+func TestBestCursor(t *testing.T) {
+	theme, err := xcursor.LoadThemeFromDir("testdata")
+	require.Nil(t, err)
+	left := theme.Cursors["left_ptr"]
+
+	img, err := theme.BestCursor("left_ptr", 24)
+	require.Nil(t, err)
+	require.Same(t, left.Images[16][0], img)
+
+	img, err = theme.BestCursor("default", 24)
+	require.Nil(t, err)
+	require.Same(t, left.Images[16][0], img)
+
+	_, err = theme.BestCursor("my-cursor", 24)
+	require.NotNil(t, err)
+
+	xcursor.Aliases["my-cursor"] = []string{"missing", "left_ptr"}
+	defer delete(xcursor.Aliases, "my-cursor")
+	img, err = theme.BestCursor("my-cursor", 24)
+	require.Nil(t, err)
+	require.Same(t, left.Images[16][0], img)
+
+	theme.Cursors["empty"] = new(xcursor.Cursor)
+	_, err = theme.BestCursor("empty", 24)
+	require.NotNil(t, err)
+}
+
+func TestWatch(t *testing.T) {
+	data, err := os.ReadFile("testdata/left_ptr")
+	require.Nil(t, err)
+
+	dir := t.TempDir()
+	require.Nil(t, os.WriteFile(filepath.Join(dir, "left_ptr"), data, 0644))
+
+	theme, err := xcursor.LoadThemeFromDir(dir)
+	require.Nil(t, err)
+	require.Len(t, theme.Cursors, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes, err := theme.Watch(ctx)
+	require.Nil(t, err)
+
+	require.Nil(t, os.WriteFile(filepath.Join(dir, "default"), data, 0644))
+	select {
+	case <-changes:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no change detected")
+	}
+
+	require.Nil(t, theme.Reload())
+	require.Len(t, theme.Cursors, 2)
+
+	cancel()
+	for range changes {
+	}
+
+	require.Nil(t, os.RemoveAll(dir))
+	require.NotNil(t, theme.Reload())
+	require.Len(t, theme.Cursors, 2)
+
+	_, err = theme.Watch(context.Background())
+	require.NotNil(t, err)
+
+	fsys := fstest.MapFS{"fs/cursors/left_ptr": {Data: data}}
+	theme, err = xcursor.LoadThemeFromFS(fsys, "fs")
+	require.Nil(t, err)
+	_, err = theme.Watch(context.Background())
+	require.NotNil(t, err)
+}
+
+func TestWatchInherits(t *testing.T) {
+	data, err := os.ReadFile("testdata/left_ptr")
+	require.Nil(t, err)
+
+	root := t.TempDir()
+	t.Setenv("XCURSOR_PATH", root)
+	for _, theme := range []string{"child", "parent"} {
+		require.Nil(t, os.MkdirAll(filepath.Join(root, theme, "cursors"), 0755))
+		require.Nil(t, os.WriteFile(filepath.Join(root, theme, "index.theme"), []byte("[Icon Theme]\n"), 0644))
+	}
+	require.Nil(t, os.WriteFile(filepath.Join(root, "child", "cursors", "left_ptr"), data, 0644))
+
+	theme, err := xcursor.LoadTheme("child")
+	require.Nil(t, err)
+	require.Len(t, theme.Cursors, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes, err := theme.Watch(ctx)
+	require.Nil(t, err)
+
+	wait := func() {
+		t.Helper()
+		select {
+		case <-changes:
+		case <-time.After(5 * time.Second):
+			t.Fatal("no change detected")
+		}
+	}
+
+	index := filepath.Join(root, "child", "index.theme")
+	require.Nil(t, os.WriteFile(index, []byte("[Icon Theme]\nInherits=parent\n"), 0644))
+	wait()
+
+	require.Nil(t, os.WriteFile(filepath.Join(root, "parent", "cursors", "hand"), data, 0644))
+	wait()
+
+	require.Nil(t, theme.Reload())
+	_, ok := theme.Cursor("hand")
+	require.True(t, ok)
+}
+
+func TestThemeCursor(t *testing.T) {
+	theme, err := xcursor.LoadThemeFromDir("testdata")
+	require.Nil(t, err)
+
+	c, ok := theme.Cursor("left_ptr")
+	require.True(t, ok)
+	require.NotNil(t, c)
+
+	c, ok = theme.Cursor("missing")
+	require.False(t, ok)
+	require.Nil(t, c)
+
+	theme.Cursors["broken"] = nil
+	c, ok = theme.Cursor("broken")
+	require.False(t, ok)
+	require.Nil(t, c)
+}
+
+func BenchmarkLoadThemeFromDir(b *testing.B) {
+	data, err := os.ReadFile("testdata/left_ptr")
+	require.Nil(b, err)
+
+	for _, n := range []int{1, 8, 64} {
+		dir := b.TempDir()
+		for i := range n {
+			err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("cursor%v", i)), data, 0644)
+			require.Nil(b, err)
+		}
+
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			b.ReportAllocs()
+			for range b.N {
+				_, err := xcursor.LoadThemeFromDir(dir)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadThemeFromFS(t *testing.T) {
+	data, err := os.ReadFile("testdata/left_ptr")
+	require.Nil(t, err)
+
+	fsys := fstest.MapFS{
+		"child/index.theme":      {Data: []byte("[Icon Theme]\nInherits=parent\n")},
+		"child/cursors/left_ptr": {Data: data},
+		"child/cursors/README":   {Data: []byte("not a cursor")},
+		"parent/cursors/hand":    {Data: data},
+	}
+
+	theme, err := xcursor.LoadThemeFromFS(fsys, "child")
+	require.Nil(t, err)
+	require.Equal(t, "child", theme.Name)
+	names, err := theme.ListCursorNames("*")
+	require.Nil(t, err)
+	require.Equal(t, []string{"hand", "left_ptr"}, names)
+
+	theme, err = xcursor.LoadThemeFromFS(fsys, "parent")
+	require.Nil(t, err)
+	require.Len(t, theme.Cursors, 1)
+
+	delete(fsys, "parent/cursors/hand")
+	fsys["parent/cursors/default"] = &fstest.MapFile{Data: data}
+	require.Nil(t, theme.Reload())
+	_, ok := theme.Cursor("default")
+	require.True(t, ok)
+	_, ok = theme.Cursor("hand")
+	require.False(t, ok)
+}
+
+func TestListThemes(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+	require.Nil(t, os.MkdirAll(filepath.Join(first, "Adwaita", "cursors"), 0755))
+	require.Nil(t, os.MkdirAll(filepath.Join(first, "hicolor", "48x48"), 0755))
+	require.Nil(t, os.MkdirAll(filepath.Join(second, "Breeze", "cursors"), 0755))
+	require.Nil(t, os.MkdirAll(filepath.Join(second, "Adwaita", "cursors"), 0755))
+	missing := filepath.Join(first, "missing")
+
+	themes, err := xcursor.ListThemesFromPath(first)
+	require.Nil(t, err)
+	require.Equal(t, []string{"Adwaita"}, themes)
+
+	_, err = xcursor.ListThemesFromPath(missing)
+	require.NotNil(t, err)
+
+	unreadable := filepath.Join(first, "unreadable")
+	require.Nil(t, os.WriteFile(unreadable, nil, 0644))
+	require.Nil(t, os.WriteFile(filepath.Join(second, "README"), nil, 0644))
+
+	var logs bytes.Buffer
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	paths := []string{second, missing, unreadable, first}
+	t.Setenv("XCURSOR_PATH", strings.Join(paths, string(filepath.ListSeparator)))
+	themes, err = xcursor.ListThemes()
+	require.Nil(t, err)
+	require.Equal(t, []string{"Adwaita", "Breeze"}, themes)
+
+	require.Contains(t, logs.String(), "level=DEBUG")
+	require.Contains(t, logs.String(), missing)
+	require.Contains(t, logs.String(), "level=WARN")
+	require.Contains(t, logs.String(), unreadable)
+	require.NotContains(t, logs.String(), "README")
+}
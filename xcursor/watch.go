@@ -0,0 +1,166 @@
+package xcursor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// watchInterval is the interval at which watched themes are polled
+// for changes.
+var watchInterval = 500 * time.Millisecond
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// Watch watches the directories, index.theme files, and override
+// files that the theme is loaded from for changes. Whenever a change
+// is detected, a value is sent on the returned channel. Multiple
+// changes that happen before the previous value has been received are
+// coalesced. The channel is closed when ctx is canceled.
+//
+// The set of watched directories is recomputed every time that they
+// are checked, so changes to the themes that the theme inherits from
+// are picked up as well. Watch returns an error if the theme was not
+// loaded from the filesystem, such as when it was loaded via
+// [LoadThemeFromFS], or if none of the files that it would be loaded
+// from exist.
+//
+// Watch does not modify the theme. To reload it after a change, call
+// [Theme.Reload].
+func (t *Theme) Watch(ctx context.Context) (<-chan struct{}, error) {
+	if t.fsys != nil {
+		return nil, errors.New("theme was not loaded from the filesystem")
+	}
+
+	name, dir := t.Name, t.dir
+	overrides := slices.Collect(maps.Values(t.Overrides))
+	check := func() (map[string]fileState, error) {
+		dirs, files := themePaths(name, dir)
+		return snapshot(dirs, append(files, overrides...))
+	}
+
+	prev, err := check()
+	if err != nil {
+		return nil, err
+	}
+	if len(prev) == 0 {
+		return nil, fmt.Errorf("no files found for theme %q", name)
+	}
+
+	c := make(chan struct{}, 1)
+	go func() {
+		defer close(c)
+
+		tick := time.NewTicker(watchInterval)
+		defer tick.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-tick.C:
+			}
+
+			cur, err := check()
+			if err != nil || maps.Equal(prev, cur) {
+				continue
+			}
+			prev = cur
+
+			select {
+			case c <- struct{}{}:
+			default:
+			}
+		}
+	}()
+
+	return c, nil
+}
+
+// themePaths returns the cursor directories and index.theme files
+// that loading a theme would read from. If dir is not empty, it is
+// the only directory that the theme is loaded from, as with
+// [LoadThemeFromDir]. Otherwise, the named theme and the themes that
+// it inherits from are looked up in the same way as [LoadTheme].
+func themePaths(name, dir string) (dirs, files []string) {
+	if dir != "" {
+		return []string{dir}, nil
+	}
+
+	var find func(string)
+	find = func(theme string) {
+		for path := range libraryPaths() {
+			index := filepath.Join(path, theme, "index.theme")
+			files = append(files, index)
+
+			inherits, err := loadInherits(index)
+			if err != nil {
+				if errors.Is(err, fs.ErrNotExist) {
+					continue
+				}
+				return
+			}
+			if inherits != nil {
+				for theme := range inherits {
+					find(theme)
+				}
+			}
+
+			dirs = append(dirs, filepath.Join(path, theme, "cursors"))
+			return
+		}
+	}
+	find(name)
+
+	return dirs, files
+}
+
+func snapshot(dirs, files []string) (map[string]fileState, error) {
+	state := make(map[string]fileState)
+	stat := func(path string) error {
+		info, err := os.Stat(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		state[path] = fileState{modTime: info.ModTime(), size: info.Size()}
+		return nil
+	}
+
+	for _, dir := range dirs {
+		err := stat(dir)
+		if err != nil {
+			return nil, fmt.Errorf("stat: %w", err)
+		}
+
+		ents, err := os.ReadDir(dir)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("read dir %q: %w", dir, err)
+		}
+		for _, ent := range ents {
+			err := stat(filepath.Join(dir, ent.Name()))
+			if err != nil {
+				return nil, fmt.Errorf("stat: %w", err)
+			}
+		}
+	}
+	for _, file := range files {
+		err := stat(file)
+		if err != nil {
+			return nil, fmt.Errorf("stat: %w", err)
+		}
+	}
+
+	return state, nil
+}